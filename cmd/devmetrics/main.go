@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -11,9 +12,10 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/vukan322/devmetrics/internal/core"
-	bitbucketprovider "github.com/vukan322/devmetrics/internal/providers/bitbucket"
-	githubprovider "github.com/vukan322/devmetrics/internal/providers/github"
-	gitlabprovider "github.com/vukan322/devmetrics/internal/providers/gitlab"
+	"github.com/vukan322/devmetrics/internal/httpclient"
+	"github.com/vukan322/devmetrics/internal/languages"
+	"github.com/vukan322/devmetrics/internal/providers"
+	_ "github.com/vukan322/devmetrics/internal/providers/all"
 	"github.com/vukan322/devmetrics/internal/render"
 )
 
@@ -21,72 +23,103 @@ func main() {
 	_ = godotenv.Load()
 
 	var (
-		user   string
-		output string
+		user          string
+		output        string
+		selected      string
+		timeout       time.Duration
+		listProviders bool
+		noCache       bool
+		clearCache    bool
+		strict        bool
+		jsonOutput    string
+		fromJSON      string
+		excludeTypes  string
+		skipRepos     string
+		includeRepos  string
+		excludeRepos  string
+		includeTopics string
+		excludeTopics string
 	)
 
 	flag.StringVar(&user, "user", "", "primary username/handle (e.g. GitHub username)")
 	flag.StringVar(&output, "out", "devmetrics.svg", "output SVG file path")
+	flag.StringVar(&selected, "providers", os.Getenv("DEV_METRICS_PROVIDERS"), "comma-separated providers to use (default: all configured)")
+	flag.DurationVar(&timeout, "timeout", 10*time.Second, "default per-provider fetch timeout (override with DEV_METRICS_<PROVIDER>_TIMEOUT)")
+	flag.BoolVar(&listProviders, "list-providers", false, "list available providers and their configuration keys")
+	flag.BoolVar(&noCache, "no-cache", false, "bypass the on-disk HTTP response cache")
+	flag.BoolVar(&clearCache, "clear-cache", false, "remove cached HTTP responses before fetching")
+	flag.BoolVar(&strict, "strict", false, "exit with an error instead of rendering when any provider or field failed")
+	flag.StringVar(&jsonOutput, "json", "", "also write the collected stats as JSON to this path")
+	flag.StringVar(&fromJSON, "from-json", "", "render from a previously saved JSON file instead of fetching")
+	flag.StringVar(&excludeTypes, "exclude-language-types", os.Getenv("DEV_METRICS_EXCLUDE_LANGUAGE_TYPES"), "comma-separated language types to leave off the card: programming, markup, data, prose")
+	flag.StringVar(&skipRepos, "skip-repos", os.Getenv("DEV_METRICS_SKIP_REPOS"), "comma-separated kinds of repos to leave out: forks, archived, mirrors, templates (default: none)")
+	flag.StringVar(&includeRepos, "include-repos", os.Getenv("DEV_METRICS_INCLUDE_REPOS"), "comma-separated name globs; only matching repos are counted")
+	flag.StringVar(&excludeRepos, "exclude-repos", os.Getenv("DEV_METRICS_EXCLUDE_REPOS"), "comma-separated name globs of repos to leave out")
+	flag.StringVar(&includeTopics, "include-topics", os.Getenv("DEV_METRICS_INCLUDE_TOPICS"), "comma-separated topics; only repos with one of them are counted")
+	flag.StringVar(&excludeTopics, "exclude-topics", os.Getenv("DEV_METRICS_EXCLUDE_TOPICS"), "comma-separated topics of repos to leave out")
 	flag.Parse()
 
-	if user == "" {
-		log.Fatal("missing required flag: -user")
+	if listProviders {
+		printProviders()
+		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	token := os.Getenv("DEV_METRICS_TOKEN")
-	if token == "" {
-		log.Println("warning: DEV_METRICS_TOKEN not set, using unauthenticated GitHub API (rate limited)")
-	}
-
-	githubProvider := githubprovider.New(token)
-
-	githubStats, err := githubProvider.Fetch(ctx, user)
-	if err != nil {
-		log.Fatalf("provider %s failed: %v", githubProvider.Name(), err)
-	}
-
-	bbEmail := os.Getenv("DEV_METRICS_BITBUCKET_EMAIL")
-	bbToken := os.Getenv("DEV_METRICS_BITBUCKET_TOKEN")
-	bbWorkspace := os.Getenv("DEV_METRICS_BITBUCKET_WORKSPACE")
-	bbUserHandle := os.Getenv("DEV_METRICS_BITBUCKET_USER")
-
-	stats := githubStats
-
-	if bbEmail != "" && bbToken != "" && bbWorkspace != "" {
-		bitbucketProvider := bitbucketprovider.New(bbEmail, bbToken, bbWorkspace)
+	var (
+		stats   core.DevStats
+		sources []string
+		failed  []string
+	)
 
-		displayHandle := bbUserHandle
-		if displayHandle == "" {
-			displayHandle = bbWorkspace
+	if fromJSON != "" {
+		data, err := os.ReadFile(fromJSON)
+		if err != nil {
+			log.Fatalf("failed to read %s: %v", fromJSON, err)
+		}
+		if stats, err = core.UnmarshalStats(data); err != nil {
+			log.Fatalf("failed to load %s: %v", fromJSON, err)
+		}
+		sources = []string{fromJSON}
+	} else {
+		if user == "" {
+			log.Fatal("missing required flag: -user")
 		}
 
-		bbStats, err := bitbucketProvider.Fetch(ctx, displayHandle)
+		types, err := languages.ParseTypes(splitList(excludeTypes))
 		if err != nil {
-			log.Printf("warning: provider %s failed: %v", bitbucketProvider.Name(), err)
-		} else {
-			stats = core.MergeStats(githubStats, bbStats)
+			log.Fatal(err)
 		}
-	} else {
-		log.Printf("info: Bitbucket env vars not set or incomplete; skipping Bitbucket provider")
-	}
 
-	glUser := os.Getenv("DEV_METRICS_GITLAB_USER")
-	glToken := os.Getenv("DEV_METRICS_GITLAB_TOKEN")
+		filter := core.RepoFilter{
+			Include:       splitList(includeRepos),
+			Exclude:       splitList(excludeRepos),
+			Topics:        splitList(includeTopics),
+			ExcludeTopics: splitList(excludeTopics),
+		}
+		if err := filter.Skip(splitList(skipRepos)); err != nil {
+			log.Fatal(err)
+		}
 
-	if glUser != "" {
-		gitlabProvider := gitlabprovider.New(glToken, glUser)
+		setupCache(noCache, clearCache)
+		stats, sources, failed = fetchStats(user, splitList(selected), timeout, filter)
+		stats = core.Finalize(stats, languages.ExcludeTypes(types...))
+	}
 
-		glStats, err := gitlabProvider.Fetch(ctx, glUser)
+	summary := stats.Diagnostics.Summary()
+	if summary != "" {
+		log.Printf("warning: incomplete data from %s", summary)
+	}
+	if strict && (len(failed) > 0 || summary != "") {
+		log.Fatal("strict mode: refusing to render incomplete stats")
+	}
+
+	if jsonOutput != "" {
+		data, err := core.MarshalStats(stats)
 		if err != nil {
-			log.Printf("warning: provider %s failed: %v", gitlabProvider.Name(), err)
-		} else {
-			stats = core.MergeStats(stats, glStats)
+			log.Fatalf("failed to encode stats: %v", err)
+		}
+		if err := os.WriteFile(jsonOutput, data, 0o644); err != nil {
+			log.Fatalf("failed to write JSON to %s: %v", jsonOutput, err)
 		}
-	} else {
-		log.Printf("info: GitLab env vars not set; skipping GitLab provider")
 	}
 
 	svg, err := render.RenderSVG(stats)
@@ -98,20 +131,126 @@ func main() {
 		log.Fatalf("failed to write SVG to %s: %v", output, err)
 	}
 
-	providersUsed := []string{"GitHub"}
-
-	if bbEmail != "" && bbToken != "" && bbWorkspace != "" {
-		providersUsed = append(providersUsed, "Bitbucket")
-	}
-
-	if glUser != "" && glToken != "" {
-		providersUsed = append(providersUsed, "GitLab")
+	if fromJSON != "" {
+		fmt.Printf("devmetrics: generated %s from %s\n", output, fromJSON)
+		return
 	}
 
 	fmt.Printf(
 		"devmetrics: generated %s for user %q via providers: %s\n",
 		output,
 		user,
-		strings.Join(providersUsed, ", "),
+		strings.Join(sources, ", "),
 	)
+	if stats.Totals.FilteredRepos > 0 {
+		fmt.Printf("devmetrics: left out %d repos matching -skip-repos and the repo filters\n", stats.Totals.FilteredRepos)
+	}
+	if stats.Totals.DuplicateRepos > 0 {
+		fmt.Printf("devmetrics: counted %d repos found on several providers once\n", stats.Totals.DuplicateRepos)
+	}
+}
+
+func fetchStats(user string, selected []string, timeout time.Duration, filter core.RepoFilter) (core.DevStats, []string, []string) {
+	if err := providers.CheckSelected(selected); err != nil {
+		log.Fatal(err)
+	}
+
+	instances, errs := providers.Load(providers.LoadOptions{
+		Lookup:         os.Getenv,
+		User:           user,
+		Selected:       selected,
+		DefaultTimeout: timeout,
+		RepoFilter:     filter,
+	})
+	for _, err := range errs {
+		log.Printf("warning: %v", err)
+	}
+	if len(instances) == 0 {
+		log.Fatal("no providers configured")
+	}
+
+	results := providers.FetchAll(context.Background(), instances)
+
+	var providersFailed []string
+	for _, res := range results {
+		if res.Err == nil {
+			continue
+		}
+		providersFailed = append(providersFailed, res.Instance.Label())
+
+		var rl *httpclient.RateLimitError
+		if errors.As(res.Err, &rl) && !rl.Reset.IsZero() {
+			log.Printf("warning: provider %s is rate limited by %s until %s", res.Instance.Label(), rl.Host, rl.Reset.Local().Format(time.Kitchen))
+			continue
+		}
+		log.Printf("warning: provider %s failed after %s: %v", res.Instance.Label(), res.Elapsed.Round(time.Millisecond), res.Err)
+	}
+
+	stats, providersUsed := providers.Merge(results)
+	if len(providersUsed) == 0 {
+		log.Fatal("all providers failed")
+	}
+
+	return stats, providersUsed, providersFailed
+}
+
+func setupCache(noCache, clearCache bool) {
+	dir := os.Getenv("DEV_METRICS_CACHE_DIR")
+	if dir == "" {
+		var err error
+		if dir, err = httpclient.DefaultCacheDir(); err != nil {
+			log.Printf("warning: HTTP cache disabled: %v", err)
+			return
+		}
+	}
+
+	cache := httpclient.NewCache(dir)
+	if clearCache {
+		if err := cache.Clear(); err != nil {
+			log.Printf("warning: %v", err)
+		}
+	}
+	if noCache {
+		return
+	}
+
+	httpclient.SetCache(cache)
+}
+
+func printProviders() {
+	for _, f := range providers.Factories() {
+		var notes []string
+		if f.Multiple {
+			notes = append(notes, "repeatable with _2, _3, ... suffixes")
+		}
+		if f.OptIn {
+			notes = append(notes, "only used when selected with -providers")
+		}
+
+		fmt.Printf("%s (%s)", f.Name, f.Title)
+		if len(notes) > 0 {
+			fmt.Printf(" [%s]", strings.Join(notes, "; "))
+		}
+		fmt.Println()
+
+		for _, key := range f.Keys {
+			usage := key.Usage
+			if key.Required {
+				usage += " (required)"
+			}
+			fmt.Printf("  %-40s %s\n", f.EnvName(key, 1), usage)
+		}
+		fmt.Printf("  %-40s %s\n", f.TimeoutEnvName(1), "fetch timeout, e.g. 30s (defaults to -timeout)")
+	}
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
 }
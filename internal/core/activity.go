@@ -0,0 +1,63 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+func FormatJoinedAgo(created time.Time) string {
+	if created.IsZero() {
+		return ""
+	}
+	years := time.Since(created).Hours() / 24 / 365
+	if years < 1 {
+		months := int(time.Since(created).Hours() / 24 / 30)
+		if months < 1 {
+			return "this month"
+		}
+		if months == 1 {
+			return "1 month ago"
+		}
+		return fmt.Sprintf("%d months ago", months)
+	}
+	y := int(years)
+	if y == 1 {
+		return "1 year ago"
+	}
+	return fmt.Sprintf("%d years ago", y)
+}
+
+func CommitsThisWeek(contribs map[time.Time]int) int {
+	today := time.Now().UTC()
+	startOfToday := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	weekStart := startOfToday.AddDate(0, 0, -6)
+
+	total := 0
+	for day, count := range contribs {
+		if !day.Before(weekStart) && !day.After(startOfToday) {
+			total += count
+		}
+	}
+	return total
+}
+
+// CountCommitPage adds the user's commits from one page of a git log to
+// contribs and reports whether any commit on the page is at or after since.
+// Logs are listed in topological order, so an old merged commit can precede
+// newer ones; callers keep paging until a page reports false.
+func CountCommitPage[T any](contribs map[time.Time]int, page []T, since time.Time, commit func(T) (time.Time, bool)) bool {
+	recent := false
+	for _, c := range page {
+		t, mine := commit(c)
+		if t.Before(since) {
+			continue
+		}
+		recent = true
+		if !mine {
+			continue
+		}
+		t = t.UTC()
+		contribs[time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)]++
+	}
+	return recent
+}
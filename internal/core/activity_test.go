@@ -0,0 +1,67 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCountCommitPage(t *testing.T) {
+	since := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	at := func(d, h int) time.Time { return time.Date(2026, 3, d, h, 0, 0, 0, time.UTC) }
+
+	type commit struct {
+		at   time.Time
+		mine bool
+	}
+
+	tests := []struct {
+		name       string
+		page       []commit
+		want       map[time.Time]int
+		wantRecent bool
+	}{
+		{
+			name:       "old merge before newer commits",
+			page:       []commit{{at(20, 9), true}, {at(2, 9), true}, {at(18, 9), true}, {at(18, 23), true}},
+			want:       map[time.Time]int{at(20, 0): 1, at(18, 0): 2},
+			wantRecent: true,
+		},
+		{
+			name:       "only other authors in the window",
+			page:       []commit{{at(15, 9), false}, {at(1, 9), true}},
+			want:       map[time.Time]int{},
+			wantRecent: true,
+		},
+		{
+			name: "whole page predates since",
+			page: []commit{{at(9, 23), true}, {at(1, 9), true}},
+			want: map[time.Time]int{},
+		},
+		{
+			name:       "since is inclusive and days are UTC",
+			page:       []commit{{since, true}, {time.Date(2026, 3, 11, 0, 30, 0, 0, time.FixedZone("CET", 3600)), true}},
+			want:       map[time.Time]int{at(10, 0): 2},
+			wantRecent: true,
+		},
+		{
+			name: "empty page",
+			want: map[time.Time]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[time.Time]int{}
+			recent := CountCommitPage(got, tt.page, since, func(c commit) (time.Time, bool) {
+				return c.at, c.mine
+			})
+			if recent != tt.wantRecent {
+				t.Errorf("recent = %v, want %v", recent, tt.wantRecent)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("contribs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Field string
+
+const (
+	FieldRepos            Field = "repos"
+	FieldStars            Field = "stars"
+	FieldFollowers        Field = "followers"
+	FieldContributedRepos Field = "contributed_repos"
+	FieldJoined           Field = "joined"
+	FieldLanguages        Field = "languages"
+	FieldContributions    Field = "contributions"
+	FieldIssues           Field = "issues"
+	FieldPullRequests     Field = "pull_requests"
+	FieldReviews          Field = "reviews"
+	FieldPackages         Field = "packages"
+)
+
+type FieldStatus string
+
+const (
+	StatusSupplied    FieldStatus = "supplied"
+	StatusFailed      FieldStatus = "failed"
+	StatusUnsupported FieldStatus = "unsupported"
+)
+
+type FieldReport struct {
+	Source string
+	Field  Field
+	Status FieldStatus
+	Error  string
+}
+
+type Diagnostics struct {
+	Reports []FieldReport
+}
+
+func (d *Diagnostics) Supplied(source string, fields ...Field) {
+	for _, f := range fields {
+		d.Reports = append(d.Reports, FieldReport{Source: source, Field: f, Status: StatusSupplied})
+	}
+}
+
+func (d *Diagnostics) Unsupported(source string, fields ...Field) {
+	for _, f := range fields {
+		d.Reports = append(d.Reports, FieldReport{Source: source, Field: f, Status: StatusUnsupported})
+	}
+}
+
+func (d *Diagnostics) Failed(source string, field Field, err error) {
+	report := FieldReport{Source: source, Field: field, Status: StatusFailed}
+	if err != nil {
+		report.Error = err.Error()
+	}
+	d.Reports = append(d.Reports, report)
+}
+
+func (d *Diagnostics) Record(source string, field Field, err error) {
+	if err != nil {
+		d.Failed(source, field, err)
+		return
+	}
+	d.Supplied(source, field)
+}
+
+func (d *Diagnostics) RecordPartial(source string, field Field, failed, total int) {
+	if failed < total || total == 0 {
+		d.Supplied(source, field)
+	}
+	if failed > 0 {
+		d.Failed(source, field, fmt.Errorf("%d of %d requests failed", failed, total))
+	}
+}
+
+func (d Diagnostics) Known(field Field) bool {
+	mentioned := false
+	for _, r := range d.Reports {
+		if r.Field != field {
+			continue
+		}
+		if r.Status == StatusSupplied {
+			return true
+		}
+		mentioned = true
+	}
+	return !mentioned
+}
+
+func (d Diagnostics) Sources(field Field) []string {
+	var out []string
+	for _, r := range d.Reports {
+		if r.Field == field && r.Status == StatusSupplied {
+			out = append(out, r.Source)
+		}
+	}
+	return out
+}
+
+func (d Diagnostics) Failures() []FieldReport {
+	var out []FieldReport
+	for _, r := range d.Reports {
+		if r.Status == StatusFailed {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (d Diagnostics) Summary() string {
+	failures := d.Failures()
+	if len(failures) == 0 {
+		return ""
+	}
+
+	bySource := make(map[string][]string)
+	for _, r := range failures {
+		bySource[r.Source] = append(bySource[r.Source], string(r.Field))
+	}
+
+	sources := make([]string, 0, len(bySource))
+	for s := range bySource {
+		sources = append(sources, s)
+	}
+	sort.Strings(sources)
+
+	parts := make([]string, 0, len(sources))
+	for _, s := range sources {
+		parts = append(parts, fmt.Sprintf("%s (%s)", s, strings.Join(bySource[s], ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func mergeDiagnostics(a, b Diagnostics) Diagnostics {
+	if len(b.Reports) == 0 {
+		return a
+	}
+
+	merged := Diagnostics{Reports: make([]FieldReport, 0, len(a.Reports)+len(b.Reports))}
+	merged.Reports = append(merged.Reports, a.Reports...)
+	merged.Reports = append(merged.Reports, b.Reports...)
+	return merged
+}
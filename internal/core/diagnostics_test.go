@@ -0,0 +1,107 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDiagnosticsKnown(t *testing.T) {
+	tests := []struct {
+		name string
+		diag func(d *Diagnostics)
+		want bool
+	}{
+		{"no provider mentioned the field", func(d *Diagnostics) {
+			d.Supplied("github", FieldStars)
+		}, true},
+		{"supplied", func(d *Diagnostics) {
+			d.Supplied("github", FieldReviews)
+		}, true},
+		{"unsupported everywhere", func(d *Diagnostics) {
+			d.Unsupported("gitlab", FieldReviews)
+			d.Unsupported("local", FieldReviews)
+		}, false},
+		{"failed", func(d *Diagnostics) {
+			d.Failed("github", FieldReviews, errors.New("boom"))
+		}, false},
+		{"supplied by another provider", func(d *Diagnostics) {
+			d.Failed("github", FieldReviews, errors.New("boom"))
+			d.Unsupported("local", FieldReviews)
+			d.Supplied("gerrit", FieldReviews)
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Diagnostics
+			tt.diag(&d)
+			if got := d.Known(FieldReviews); got != tt.want {
+				t.Errorf("Known(reviews) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsRecordPartial(t *testing.T) {
+	tests := []struct {
+		name          string
+		failed, total int
+		want          []FieldReport
+	}{
+		{"nothing to request", 0, 0, []FieldReport{
+			{Source: "gitea", Field: FieldLanguages, Status: StatusSupplied},
+		}},
+		{"all succeeded", 0, 4, []FieldReport{
+			{Source: "gitea", Field: FieldLanguages, Status: StatusSupplied},
+		}},
+		{"some failed", 1, 4, []FieldReport{
+			{Source: "gitea", Field: FieldLanguages, Status: StatusSupplied},
+			{Source: "gitea", Field: FieldLanguages, Status: StatusFailed, Error: "1 of 4 requests failed"},
+		}},
+		{"all failed", 4, 4, []FieldReport{
+			{Source: "gitea", Field: FieldLanguages, Status: StatusFailed, Error: "4 of 4 requests failed"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Diagnostics
+			d.RecordPartial("gitea", FieldLanguages, tt.failed, tt.total)
+			if !reflect.DeepEqual(d.Reports, tt.want) {
+				t.Errorf("reports = %+v, want %+v", d.Reports, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		diag func(d *Diagnostics)
+		want string
+	}{
+		{"empty", func(d *Diagnostics) {}, ""},
+		{"no failures", func(d *Diagnostics) {
+			d.Supplied("github", FieldRepos, FieldStars)
+			d.Unsupported("local", FieldStars)
+		}, ""},
+		{"grouped and sorted by source", func(d *Diagnostics) {
+			d.Failed("gitlab", FieldIssues, errors.New("boom"))
+			d.Record("github", FieldRepos, nil)
+			d.Record("github", FieldReviews, errors.New("boom"))
+			d.Failed("gitlab", FieldPullRequests, nil)
+			d.RecordPartial("github", FieldLanguages, 2, 3)
+		}, "github (reviews, languages); gitlab (issues, pull_requests)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Diagnostics
+			tt.diag(&d)
+			if got := d.Summary(); got != tt.want {
+				t.Errorf("Summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
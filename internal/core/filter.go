@@ -0,0 +1,94 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+type RepoFilter struct {
+	SkipForks     bool
+	SkipArchived  bool
+	SkipMirrors   bool
+	SkipTemplates bool
+	Include       []string
+	Exclude       []string
+	Topics        []string
+	ExcludeTopics []string
+}
+
+type RepoMeta struct {
+	Name     string
+	FullName string
+	Fork     bool
+	Archived bool
+	Mirror   bool
+	Template bool
+	Topics   []string
+}
+
+func (f *RepoFilter) Skip(kinds []string) error {
+	for _, kind := range kinds {
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "forks":
+			f.SkipForks = true
+		case "archived":
+			f.SkipArchived = true
+		case "mirrors":
+			f.SkipMirrors = true
+		case "templates":
+			f.SkipTemplates = true
+		case "none":
+		default:
+			return fmt.Errorf("core: unknown repo kind %q (want forks, archived, mirrors, templates or none)", kind)
+		}
+	}
+	return nil
+}
+
+func (f RepoFilter) Keep(r RepoMeta) bool {
+	switch {
+	case f.SkipForks && r.Fork,
+		f.SkipArchived && r.Archived,
+		f.SkipMirrors && r.Mirror,
+		f.SkipTemplates && r.Template:
+		return false
+	}
+
+	if len(f.Include) > 0 && !matchRepoName(f.Include, r) {
+		return false
+	}
+	if matchRepoName(f.Exclude, r) {
+		return false
+	}
+	if len(f.Topics) > 0 && !hasTopic(f.Topics, r.Topics) {
+		return false
+	}
+	return !hasTopic(f.ExcludeTopics, r.Topics)
+}
+
+func matchRepoName(patterns []string, r RepoMeta) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		for _, name := range []string{r.Name, r.FullName} {
+			if name == "" {
+				continue
+			}
+			if ok, _ := path.Match(pattern, strings.ToLower(name)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasTopic(want, topics []string) bool {
+	for _, w := range want {
+		for _, t := range topics {
+			if strings.EqualFold(w, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
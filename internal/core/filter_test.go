@@ -0,0 +1,80 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRepoFilterSkip(t *testing.T) {
+	tests := []struct {
+		kinds   []string
+		want    RepoFilter
+		wantErr string
+	}{
+		{kinds: nil, want: RepoFilter{}},
+		{kinds: []string{"none"}, want: RepoFilter{}},
+		{kinds: []string{"forks", " Mirrors "}, want: RepoFilter{SkipForks: true, SkipMirrors: true}},
+		{kinds: []string{"archived", "templates"}, want: RepoFilter{SkipArchived: true, SkipTemplates: true}},
+		{kinds: []string{"forks", "stale"}, wantErr: `unknown repo kind "stale"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.kinds, ","), func(t *testing.T) {
+			var f RepoFilter
+			err := f.Skip(tt.kinds)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Skip(%q) error = %v, want containing %q", tt.kinds, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Skip(%q): %v", tt.kinds, err)
+			}
+			if f.SkipForks != tt.want.SkipForks || f.SkipArchived != tt.want.SkipArchived ||
+				f.SkipMirrors != tt.want.SkipMirrors || f.SkipTemplates != tt.want.SkipTemplates {
+				t.Errorf("Skip(%q) = %+v, want %+v", tt.kinds, f, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoFilterKeep(t *testing.T) {
+	engine := RepoMeta{Name: "engine", FullName: "ada/engine", Topics: []string{"Go", "cli"}}
+	fork := RepoMeta{Name: "linux", FullName: "ada/linux", Fork: true}
+
+	tests := []struct {
+		name   string
+		filter RepoFilter
+		repo   RepoMeta
+		want   bool
+	}{
+		{"empty filter keeps everything", RepoFilter{}, fork, true},
+		{"skip forks", RepoFilter{SkipForks: true}, fork, false},
+		{"skip forks keeps sources", RepoFilter{SkipForks: true}, engine, true},
+		{"skip archived", RepoFilter{SkipArchived: true}, RepoMeta{Name: "old", Archived: true}, false},
+		{"skip mirrors", RepoFilter{SkipMirrors: true}, RepoMeta{Name: "copy", Mirror: true}, false},
+		{"skip templates", RepoFilter{SkipTemplates: true}, RepoMeta{Name: "starter", Template: true}, false},
+		{"include glob on name", RepoFilter{Include: []string{"eng*"}}, engine, true},
+		{"include glob on full name", RepoFilter{Include: []string{"ada/*"}}, engine, true},
+		{"include is case insensitive", RepoFilter{Include: []string{"ADA/Engine"}}, engine, true},
+		{"include miss", RepoFilter{Include: []string{"bob/*", "site"}}, engine, false},
+		{"exclude glob on name", RepoFilter{Exclude: []string{"*gine"}}, engine, false},
+		{"exclude glob on full name", RepoFilter{Exclude: []string{"ada/e?gine"}}, engine, false},
+		{"exclude wins over include", RepoFilter{Include: []string{"ada/*"}, Exclude: []string{"engine"}}, engine, false},
+		{"glob does not cross slashes", RepoFilter{Include: []string{"*"}}, RepoMeta{FullName: "ada/engine"}, false},
+		{"topic match", RepoFilter{Topics: []string{"go"}}, engine, true},
+		{"topic miss", RepoFilter{Topics: []string{"rust"}}, engine, false},
+		{"topic required but none set", RepoFilter{Topics: []string{"go"}}, fork, false},
+		{"excluded topic", RepoFilter{ExcludeTopics: []string{"CLI"}}, engine, false},
+		{"excluded topic absent", RepoFilter{ExcludeTopics: []string{"web"}}, engine, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Keep(tt.repo); got != tt.want {
+				t.Errorf("Keep(%+v) = %v, want %v", tt.repo, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	SchemaVersion  = 2
+	jsonDateFormat = "2006-01-02"
+)
+
+type jsonStats struct {
+	SchemaVersion int              `json:"schema_version"`
+	Identity      jsonIdentity     `json:"identity"`
+	Totals        jsonTotals       `json:"totals"`
+	Activity      jsonActivity     `json:"activity"`
+	Packages      jsonPackages     `json:"packages"`
+	Repositories  []jsonRepository `json:"repositories"`
+	Diagnostics   []jsonDiagnosis  `json:"diagnostics"`
+}
+
+type jsonIdentity struct {
+	Name     string   `json:"name"`
+	Username string   `json:"username"`
+	Avatar   string   `json:"avatar,omitempty"`
+	Handles  []string `json:"handles"`
+}
+
+type jsonTotals struct {
+	PublicRepos      int    `json:"public_repos"`
+	PrivateRepos     int    `json:"private_repos"`
+	Stars            int    `json:"stars"`
+	Followers        int    `json:"followers"`
+	Following        int    `json:"following"`
+	ContributedRepos int    `json:"contributed_repos"`
+	Joined           string `json:"joined,omitempty"`
+	TotalLanguages   int    `json:"total_languages"`
+	Commits          int    `json:"commits"`
+	CurrentStreak    int    `json:"current_streak"`
+	LongestStreak    int    `json:"longest_streak"`
+	CommitsThisWeek  int    `json:"commits_this_week"`
+	Reviews          int    `json:"reviews"`
+	FilteredRepos    int    `json:"filtered_repos"`
+	DuplicateRepos   int    `json:"duplicate_repos"`
+}
+
+type jsonLanguage struct {
+	Name       string  `json:"name"`
+	Bytes      int64   `json:"bytes"`
+	Lines      int64   `json:"lines,omitempty"`
+	Percentage float64 `json:"percentage"`
+	Color      string  `json:"color,omitempty"`
+}
+
+type jsonIssues struct {
+	Open   int `json:"open"`
+	Closed int `json:"closed"`
+}
+
+type jsonPullRequests struct {
+	Open   int `json:"open"`
+	Merged int `json:"merged"`
+	Closed int `json:"closed"`
+}
+
+type jsonActivity struct {
+	ContributionsPerDay map[string]int   `json:"contributions_per_day"`
+	TopLanguages        []jsonLanguage   `json:"top_languages"`
+	Issues              jsonIssues       `json:"issues"`
+	PullRequests        jsonPullRequests `json:"pull_requests"`
+}
+
+type jsonPackage struct {
+	Name      string `json:"name"`
+	Registry  string `json:"registry"`
+	Downloads *int64 `json:"downloads"`
+}
+
+type jsonPackages struct {
+	Count          int           `json:"count"`
+	TotalDownloads int64         `json:"total_downloads"`
+	Top            []jsonPackage `json:"top"`
+}
+
+type jsonRepository struct {
+	Provider  string         `json:"provider"`
+	Name      string         `json:"name"`
+	URL       string         `json:"url,omitempty"`
+	Private   bool           `json:"private"`
+	Stars     int            `json:"stars"`
+	Source    string         `json:"source,omitempty"`
+	HeadSHA   string         `json:"head_sha,omitempty"`
+	Languages []jsonLanguage `json:"languages"`
+}
+
+type jsonDiagnosis struct {
+	Source string      `json:"source"`
+	Field  Field       `json:"field"`
+	Status FieldStatus `json:"status"`
+	Error  string      `json:"error,omitempty"`
+}
+
+func MarshalStats(stats DevStats) ([]byte, error) {
+	out := jsonStats{
+		SchemaVersion: SchemaVersion,
+		Identity: jsonIdentity{
+			Name:     stats.Identity.Name,
+			Username: stats.Identity.Username,
+			Avatar:   stats.Identity.Avatar,
+			Handles:  nonNil(stats.Identity.Handles),
+		},
+		Totals: marshalTotals(stats.Totals),
+		Activity: jsonActivity{
+			ContributionsPerDay: make(map[string]int, len(stats.Activity.ContributionsPerDay)),
+			TopLanguages:        make([]jsonLanguage, 0, len(stats.Activity.TopLanguages)),
+			Issues:              jsonIssues(stats.Activity.Issues),
+			PullRequests:        jsonPullRequests(stats.Activity.PullRequests),
+		},
+		Packages: jsonPackages{
+			Count:          stats.Packages.Count,
+			TotalDownloads: stats.Packages.TotalDownloads,
+			Top:            make([]jsonPackage, 0, len(stats.Packages.Top)),
+		},
+		Repositories: make([]jsonRepository, 0, len(stats.Repositories)),
+		Diagnostics:  make([]jsonDiagnosis, 0, len(stats.Diagnostics.Reports)),
+	}
+
+	for day, count := range stats.Activity.ContributionsPerDay {
+		out.Activity.ContributionsPerDay[day.Format(jsonDateFormat)] += count
+	}
+	for _, l := range stats.Activity.TopLanguages {
+		out.Activity.TopLanguages = append(out.Activity.TopLanguages, jsonLanguage(l))
+	}
+	for _, p := range stats.Packages.Top {
+		pkg := jsonPackage{Name: p.Name, Registry: p.Registry}
+		if !p.DownloadsUnknown {
+			pkg.Downloads = &p.Downloads
+		}
+		out.Packages.Top = append(out.Packages.Top, pkg)
+	}
+	for _, r := range stats.Repositories {
+		repo := jsonRepository{
+			Provider:  r.Provider,
+			Name:      r.Name,
+			URL:       r.URL,
+			Private:   r.Private,
+			Stars:     r.Stars,
+			Source:    r.Source,
+			HeadSHA:   r.HeadSHA,
+			Languages: make([]jsonLanguage, 0, len(r.Languages)),
+		}
+		for _, l := range r.Languages {
+			repo.Languages = append(repo.Languages, jsonLanguage(l))
+		}
+		out.Repositories = append(out.Repositories, repo)
+	}
+	for _, r := range stats.Diagnostics.Reports {
+		out.Diagnostics = append(out.Diagnostics, jsonDiagnosis(r))
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("core: marshal stats: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func UnmarshalStats(data []byte) (DevStats, error) {
+	var in jsonStats
+	if err := json.Unmarshal(data, &in); err != nil {
+		return DevStats{}, fmt.Errorf("core: unmarshal stats: %w", err)
+	}
+
+	if in.SchemaVersion == 0 {
+		return DevStats{}, fmt.Errorf("core: unmarshal stats: missing schema_version")
+	}
+	if in.SchemaVersion == 1 {
+		return DevStats{}, fmt.Errorf("core: unmarshal stats: schema_version 1 stores joined_ago instead of a join date; write the file again with -json")
+	}
+	if in.SchemaVersion > SchemaVersion {
+		return DevStats{}, fmt.Errorf("core: unmarshal stats: schema_version %d is newer than supported version %d", in.SchemaVersion, SchemaVersion)
+	}
+
+	stats := DevStats{
+		Identity: Identity{
+			Name:     in.Identity.Name,
+			Username: in.Identity.Username,
+			Avatar:   in.Identity.Avatar,
+			Handles:  in.Identity.Handles,
+		},
+		Activity: Activity{
+			Issues:       IssueStats(in.Activity.Issues),
+			PullRequests: PRStats(in.Activity.PullRequests),
+		},
+		Packages: Packages{
+			Count:          in.Packages.Count,
+			TotalDownloads: in.Packages.TotalDownloads,
+		},
+	}
+
+	totals, err := unmarshalTotals(in.Totals)
+	if err != nil {
+		return DevStats{}, fmt.Errorf("core: unmarshal stats: %w", err)
+	}
+	stats.Totals = totals
+
+	if len(in.Activity.ContributionsPerDay) > 0 {
+		stats.Activity.ContributionsPerDay = make(map[time.Time]int, len(in.Activity.ContributionsPerDay))
+		for date, count := range in.Activity.ContributionsPerDay {
+			day, err := time.ParseInLocation(jsonDateFormat, date, time.UTC)
+			if err != nil {
+				return DevStats{}, fmt.Errorf("core: unmarshal stats: invalid contribution date %q: %w", date, err)
+			}
+			stats.Activity.ContributionsPerDay[day] += count
+		}
+	}
+	for _, l := range in.Activity.TopLanguages {
+		stats.Activity.TopLanguages = append(stats.Activity.TopLanguages, LanguageStat(l))
+	}
+	for _, p := range in.Packages.Top {
+		pkg := PackageStat{Name: p.Name, Registry: p.Registry, DownloadsUnknown: p.Downloads == nil}
+		if p.Downloads != nil {
+			pkg.Downloads = *p.Downloads
+		}
+		stats.Packages.Top = append(stats.Packages.Top, pkg)
+	}
+	for _, r := range in.Repositories {
+		repo := Repository{
+			Provider: r.Provider,
+			Name:     r.Name,
+			URL:      r.URL,
+			Private:  r.Private,
+			Stars:    r.Stars,
+			Source:   r.Source,
+			HeadSHA:  r.HeadSHA,
+		}
+		for _, l := range r.Languages {
+			repo.Languages = append(repo.Languages, LanguageStat(l))
+		}
+		stats.Repositories = append(stats.Repositories, repo)
+	}
+	for _, d := range in.Diagnostics {
+		stats.Diagnostics.Reports = append(stats.Diagnostics.Reports, FieldReport(d))
+	}
+
+	return stats, nil
+}
+
+func marshalTotals(t Totals) jsonTotals {
+	out := jsonTotals{
+		PublicRepos:      t.PublicRepos,
+		PrivateRepos:     t.PrivateRepos,
+		Stars:            t.Stars,
+		Followers:        t.Followers,
+		Following:        t.Following,
+		ContributedRepos: t.ContributedRepos,
+		TotalLanguages:   t.TotalLanguages,
+		Commits:          t.Commits,
+		CurrentStreak:    t.CurrentStreak,
+		LongestStreak:    t.LongestStreak,
+		CommitsThisWeek:  t.CommitsThisWeek,
+		Reviews:          t.Reviews,
+		FilteredRepos:    t.FilteredRepos,
+		DuplicateRepos:   t.DuplicateRepos,
+	}
+	if !t.Joined.IsZero() {
+		out.Joined = t.Joined.UTC().Format(jsonDateFormat)
+	}
+	return out
+}
+
+func unmarshalTotals(in jsonTotals) (Totals, error) {
+	t := Totals{
+		PublicRepos:      in.PublicRepos,
+		PrivateRepos:     in.PrivateRepos,
+		Stars:            in.Stars,
+		Followers:        in.Followers,
+		Following:        in.Following,
+		ContributedRepos: in.ContributedRepos,
+		TotalLanguages:   in.TotalLanguages,
+		Commits:          in.Commits,
+		CurrentStreak:    in.CurrentStreak,
+		LongestStreak:    in.LongestStreak,
+		CommitsThisWeek:  in.CommitsThisWeek,
+		Reviews:          in.Reviews,
+		FilteredRepos:    in.FilteredRepos,
+		DuplicateRepos:   in.DuplicateRepos,
+	}
+	if in.Joined != "" {
+		joined, err := time.ParseInLocation(jsonDateFormat, in.Joined, time.UTC)
+		if err != nil {
+			return Totals{}, fmt.Errorf("invalid joined date %q: %w", in.Joined, err)
+		}
+		t.Joined = joined
+	}
+	return t, nil
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,221 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleStats() DevStats {
+	var diag Diagnostics
+	diag.Supplied("github", FieldRepos, FieldStars)
+	diag.Failed("gitlab.example.com", FieldIssues, errors.New("unexpected status 500"))
+	diag.Unsupported("gerrit", FieldStars)
+
+	return DevStats{
+		Identity: Identity{
+			Name:     "Ada Lovelace",
+			Username: "ada",
+			Handles:  []string{"github: ada", "gitlab: ada"},
+		},
+		Totals: Totals{
+			PublicRepos:      12,
+			PrivateRepos:     3,
+			Stars:            42,
+			Followers:        7,
+			Following:        2,
+			ContributedRepos: 5,
+			Joined:           time.Date(2021, 6, 14, 0, 0, 0, 0, time.UTC),
+			TotalLanguages:   2,
+			Commits:          9,
+			CurrentStreak:    2,
+			LongestStreak:    3,
+			CommitsThisWeek:  4,
+			Reviews:          1,
+			FilteredRepos:    2,
+			DuplicateRepos:   1,
+		},
+		Activity: Activity{
+			ContributionsPerDay: map[time.Time]int{
+				time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC): 4,
+				time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC): 5,
+			},
+			TopLanguages: []LanguageStat{
+				{Name: "Go", Bytes: 3072, Percentage: 75, Color: "#00ADD8"},
+				{Name: "Shell", Bytes: 1024, Percentage: 25, Color: "#89e051"},
+			},
+			Issues:       IssueStats{Open: 1, Closed: 2},
+			PullRequests: PRStats{Open: 3, Merged: 4, Closed: 5},
+		},
+		Packages: Packages{
+			Count:          2,
+			TotalDownloads: 1200,
+			Top: []PackageStat{
+				{Name: "left-pad", Registry: "npm", Downloads: 1200},
+				{Name: "github.com/ada/engine", Registry: "go", DownloadsUnknown: true},
+			},
+		},
+		Repositories: []Repository{
+			{
+				Provider:  "github",
+				Name:      "ada/engine",
+				URL:       "https://github.com/ada/engine",
+				Stars:     42,
+				HeadSHA:   "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+				Languages: []LanguageStat{{Name: "Go", Bytes: 3072}},
+			},
+			{
+				Provider: "codeberg.org",
+				Name:     "ada/notes",
+				URL:      "https://codeberg.org/ada/notes",
+				Private:  true,
+				Source:   "https://github.com/ada/notes",
+			},
+		},
+		Diagnostics: diag,
+	}
+}
+
+func TestMarshalStatsRoundTrip(t *testing.T) {
+	want := sampleStats()
+
+	data, err := MarshalStats(want)
+	if err != nil {
+		t.Fatalf("MarshalStats: %v", err)
+	}
+
+	got, err := UnmarshalStats(data)
+	if err != nil {
+		t.Fatalf("UnmarshalStats: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestMarshalStatsSchema(t *testing.T) {
+	data, err := MarshalStats(sampleStats())
+	if err != nil {
+		t.Fatalf("MarshalStats: %v", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	if v, ok := doc["schema_version"].(float64); !ok || int(v) != SchemaVersion {
+		t.Errorf("schema_version = %v, want %d", doc["schema_version"], SchemaVersion)
+	}
+
+	activity := doc["activity"].(map[string]any)
+	contribs := activity["contributions_per_day"].(map[string]any)
+	if contribs["2026-03-01"] != float64(4) || contribs["2026-03-02"] != float64(5) {
+		t.Errorf("contributions_per_day = %v, want YYYY-MM-DD keys", contribs)
+	}
+
+	for _, key := range []string{"identity", "totals", "activity", "packages", "repositories", "diagnostics"} {
+		if _, ok := doc[key]; !ok {
+			t.Errorf("missing top-level key %q", key)
+		}
+	}
+
+	totals := doc["totals"].(map[string]any)
+	wantTotals := map[string]any{
+		"joined":          "2021-06-14",
+		"reviews":         float64(1),
+		"filtered_repos":  float64(2),
+		"duplicate_repos": float64(1),
+	}
+	for key, want := range wantTotals {
+		if totals[key] != want {
+			t.Errorf("totals.%s = %v, want %v", key, totals[key], want)
+		}
+	}
+	if _, ok := totals["joined_ago"]; ok {
+		t.Errorf("totals has relative joined_ago, want only the joined date")
+	}
+
+	lang := activity["top_languages"].([]any)[0].(map[string]any)
+	if lang["bytes"] != float64(3072) {
+		t.Errorf("top_languages[0].bytes = %v, want 3072", lang["bytes"])
+	}
+
+	top := doc["packages"].(map[string]any)["top"].([]any)
+	if npm := top[0].(map[string]any); npm["downloads"] != float64(1200) {
+		t.Errorf("packages.top[0].downloads = %v, want 1200", npm["downloads"])
+	}
+	if goModule := top[1].(map[string]any); goModule["downloads"] != nil {
+		t.Errorf("packages.top[1].downloads = %v, want null for a registry without download counts", goModule["downloads"])
+	}
+
+	repos := doc["repositories"].([]any)
+	if len(repos) != 2 {
+		t.Fatalf("repositories = %v, want 2 entries", repos)
+	}
+	repo := repos[0].(map[string]any)
+	wantRepo := map[string]any{
+		"provider": "github",
+		"name":     "ada/engine",
+		"url":      "https://github.com/ada/engine",
+		"private":  false,
+		"stars":    float64(42),
+		"head_sha": "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+	}
+	for key, want := range wantRepo {
+		if repo[key] != want {
+			t.Errorf("repositories[0].%s = %v, want %v", key, repo[key], want)
+		}
+	}
+	if _, ok := repo["source"]; ok {
+		t.Errorf("repositories[0] has empty source, want it left out")
+	}
+	if mirror := repos[1].(map[string]any); mirror["source"] != "https://github.com/ada/notes" || mirror["private"] != true {
+		t.Errorf("repositories[1] = %v, want private mirror of github.com/ada/notes", mirror)
+	}
+}
+
+func TestMarshalStatsEmpty(t *testing.T) {
+	data, err := MarshalStats(DevStats{})
+	if err != nil {
+		t.Fatalf("MarshalStats: %v", err)
+	}
+
+	for _, want := range []string{`"handles": []`, `"top_languages": []`, `"contributions_per_day": {}`, `"repositories": []`, `"diagnostics": []`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("empty stats output missing %s:\n%s", want, data)
+		}
+	}
+
+	if _, err := UnmarshalStats(data); err != nil {
+		t.Errorf("UnmarshalStats(empty): %v", err)
+	}
+}
+
+func TestUnmarshalStatsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"invalid json", `{`, "unmarshal stats"},
+		{"missing version", `{"identity": {}}`, "missing schema_version"},
+		{"version 1", `{"schema_version": 1, "totals": {"joined_ago": "3 years ago"}}`, "write the file again with -json"},
+		{"newer version", `{"schema_version": 99}`, "newer than supported"},
+		{"bad date", `{"schema_version": 2, "activity": {"contributions_per_day": {"03/01/2026": 1}}}`, "invalid contribution date"},
+		{"bad joined date", `{"schema_version": 2, "totals": {"joined": "3 years ago"}}`, "invalid joined date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := UnmarshalStats([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("UnmarshalStats(%s) error = %v, want containing %q", tt.data, err, tt.want)
+			}
+		})
+	}
+}
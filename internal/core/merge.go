@@ -3,8 +3,12 @@ package core
 import (
 	"sort"
 	"time"
+
+	"github.com/vukan322/devmetrics/internal/languages"
 )
 
+const maxTopLanguages = 9
+
 func MergeStats(primary, secondary DevStats) DevStats {
 	merged := primary
 
@@ -18,6 +22,12 @@ func MergeStats(primary, secondary DevStats) DevStats {
 	merged.Totals.ContributedRepos += secondary.Totals.ContributedRepos
 	merged.Totals.Commits += secondary.Totals.Commits
 	merged.Totals.CommitsThisWeek += secondary.Totals.CommitsThisWeek
+	merged.Totals.Reviews += secondary.Totals.Reviews
+	merged.Totals.FilteredRepos += secondary.Totals.FilteredRepos
+	merged.Totals.DuplicateRepos += secondary.Totals.DuplicateRepos
+	if joined := secondary.Totals.Joined; !joined.IsZero() && (merged.Totals.Joined.IsZero() || joined.Before(merged.Totals.Joined)) {
+		merged.Totals.Joined = joined
+	}
 
 	merged.Activity.Issues.Open += secondary.Activity.Issues.Open
 	merged.Activity.Issues.Closed += secondary.Activity.Issues.Closed
@@ -35,12 +45,20 @@ func MergeStats(primary, secondary DevStats) DevStats {
 		}
 	}
 
-	langs, totalLangs := mergeLanguageStats(
+	merged.Activity.TopLanguages = mergeLanguageStats(
 		merged.Activity.TopLanguages,
 		secondary.Activity.TopLanguages,
 	)
-	merged.Activity.TopLanguages = langs
-	merged.Totals.TotalLanguages = totalLangs
+
+	repos, duplicates := mergeRepositories(merged.Repositories, secondary.Repositories)
+	merged.Repositories = repos
+	merged.Totals.DuplicateRepos += duplicates
+	if len(repos) > 0 {
+		applyRepositoryTotals(&merged)
+	}
+
+	merged.Packages = mergePackages(merged.Packages, secondary.Packages)
+	merged.Diagnostics = mergeDiagnostics(merged.Diagnostics, secondary.Diagnostics)
 
 	current, longest := ComputeStreaks(merged.Activity.ContributionsPerDay)
 	merged.Totals.CurrentStreak = current
@@ -49,63 +67,103 @@ func MergeStats(primary, secondary DevStats) DevStats {
 	return merged
 }
 
-func mergeLanguageStats(a, b []LanguageStat) ([]LanguageStat, int) {
+func mergeLanguageStats(a, b []LanguageStat) []LanguageStat {
 	if len(a) == 0 && len(b) == 0 {
-		return nil, 0
+		return nil
 	}
 
-	m := make(map[string]LanguageStat)
+	index := make(map[string]int, len(a)+len(b))
+	result := make([]LanguageStat, 0, len(a)+len(b))
 
-	for _, ls := range a {
-		m[ls.Name] = ls
-	}
+	for _, ls := range append(append([]LanguageStat{}, a...), b...) {
+		i, ok := index[ls.Name]
+		if !ok {
+			index[ls.Name] = len(result)
+			result = append(result, LanguageStat{Name: ls.Name, Color: ls.Color})
+			i = len(result) - 1
+		}
 
-	for _, ls := range b {
-		if existing, ok := m[ls.Name]; ok {
-			totalPct := existing.Percentage + ls.Percentage
-			color := existing.Color
-			if color == "" {
-				color = ls.Color
-			}
-			if color == "" {
-				color = "#586069"
-			}
-			m[ls.Name] = LanguageStat{
-				Name:       ls.Name,
-				Percentage: totalPct,
-				Color:      color,
-			}
-		} else {
-			color := ls.Color
-			if color == "" {
-				color = "#586069"
-			}
-			m[ls.Name] = LanguageStat{
-				Name:       ls.Name,
-				Percentage: ls.Percentage,
-				Color:      color,
-			}
+		result[i].Bytes += ls.Bytes
+		result[i].Lines += ls.Lines
+		if result[i].Color == "" {
+			result[i].Color = ls.Color
 		}
 	}
 
-	result := make([]LanguageStat, 0, len(m))
-	var total float64
-	for _, ls := range m {
-		total += ls.Percentage
-		result = append(result, ls)
+	return result
+}
+
+func Finalize(stats DevStats, exclude func(name string) bool) DevStats {
+	langs, total := summarizeLanguages(stats.Activity.TopLanguages, exclude)
+	stats.Activity.TopLanguages = langs
+	stats.Totals.TotalLanguages = total
+	return stats
+}
+
+func summarizeLanguages(in []LanguageStat, exclude func(name string) bool) ([]LanguageStat, int) {
+	langs := mergeLanguageStats(nil, in)
+
+	var total int64
+	kept := langs[:0]
+	for _, ls := range langs {
+		if ls.Bytes <= 0 || (exclude != nil && exclude(ls.Name)) {
+			continue
+		}
+		if ls.Color == "" {
+			ls.Color = languages.DefaultColor
+		}
+		total += ls.Bytes
+		kept = append(kept, ls)
+	}
+	langs = kept
+	if len(langs) == 0 {
+		return nil, 0
 	}
 
-	if total > 0 {
-		for i := range result {
-			result[i].Percentage = result[i].Percentage / total * 100.0
+	totalLanguages := len(langs)
+
+	sort.Slice(langs, func(i, j int) bool {
+		if langs[i].Bytes != langs[j].Bytes {
+			return langs[i].Bytes > langs[j].Bytes
 		}
+		return langs[i].Name < langs[j].Name
+	})
+
+	if len(langs) > maxTopLanguages {
+		others := LanguageStat{Name: "Others", Color: languages.DefaultColor}
+		for _, ls := range langs[maxTopLanguages:] {
+			others.Bytes += ls.Bytes
+			others.Lines += ls.Lines
+		}
+		langs = append(langs[:maxTopLanguages], others)
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Percentage > result[j].Percentage
+	for i := range langs {
+		langs[i].Percentage = float64(langs[i].Bytes) / float64(total) * 100.0
+	}
+
+	return langs, totalLanguages
+}
+
+func mergePackages(a, b Packages) Packages {
+	merged := Packages{
+		Count:          a.Count + b.Count,
+		TotalDownloads: a.TotalDownloads + b.TotalDownloads,
+	}
+
+	if len(a.Top) == 0 && len(b.Top) == 0 {
+		return merged
+	}
+
+	merged.Top = make([]PackageStat, 0, len(a.Top)+len(b.Top))
+	merged.Top = append(merged.Top, a.Top...)
+	merged.Top = append(merged.Top, b.Top...)
+
+	sort.SliceStable(merged.Top, func(i, j int) bool {
+		return merged.Top[i].Downloads > merged.Top[j].Downloads
 	})
 
-	return result, len(result)
+	return merged
 }
 
 func ComputeStreaks(contribs map[time.Time]int) (int, int) {
@@ -0,0 +1,322 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/vukan322/devmetrics/internal/languages"
+)
+
+func langStats(langs ...LanguageStat) DevStats {
+	return DevStats{Activity: Activity{TopLanguages: langs}}
+}
+
+func permutations(n int) [][]int {
+	if n == 1 {
+		return [][]int{{0}}
+	}
+
+	var out [][]int
+	for _, p := range permutations(n - 1) {
+		for i := 0; i <= len(p); i++ {
+			perm := make([]int, 0, n)
+			perm = append(perm, p[:i]...)
+			perm = append(perm, n-1)
+			perm = append(perm, p[i:]...)
+			out = append(out, perm)
+		}
+	}
+	return out
+}
+
+func manyLanguages(prefix string, n int, bytes int64) DevStats {
+	var stats DevStats
+	for i := 0; i < n; i++ {
+		stats.Activity.TopLanguages = append(stats.Activity.TopLanguages, LanguageStat{
+			Name:  fmt.Sprintf("%s%02d", prefix, i),
+			Bytes: bytes,
+		})
+	}
+	return stats
+}
+
+func TestFinalizeLanguagesIgnoresMergeOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []DevStats
+		want      []LanguageStat
+		wantTotal int
+	}{
+		{
+			name: "weights by bytes not by provider",
+			providers: []DevStats{
+				langStats(LanguageStat{Name: "Go", Bytes: 1000}),
+				langStats(LanguageStat{Name: "Rust", Bytes: 150000}, LanguageStat{Name: "Go", Bytes: 49000}),
+			},
+			want: []LanguageStat{
+				{Name: "Rust", Bytes: 150000, Percentage: 75, Color: languages.DefaultColor},
+				{Name: "Go", Bytes: 50000, Percentage: 25, Color: languages.DefaultColor},
+			},
+			wantTotal: 2,
+		},
+		{
+			name: "keeps first known color",
+			providers: []DevStats{
+				langStats(LanguageStat{Name: "Go", Bytes: 300}),
+				langStats(LanguageStat{Name: "Go", Bytes: 100, Color: "#00ADD8"}),
+				langStats(LanguageStat{Name: "Shell", Bytes: 0}),
+			},
+			want: []LanguageStat{
+				{Name: "Go", Bytes: 400, Percentage: 100, Color: "#00ADD8"},
+			},
+			wantTotal: 1,
+		},
+		{
+			name: "ties sort by name",
+			providers: []DevStats{
+				langStats(LanguageStat{Name: "Zig", Bytes: 50}),
+				langStats(LanguageStat{Name: "Ada", Bytes: 50}),
+				langStats(LanguageStat{Name: "Lua", Bytes: 50}, LanguageStat{Name: "C", Bytes: 50}),
+			},
+			want: []LanguageStat{
+				{Name: "Ada", Bytes: 50, Percentage: 25, Color: languages.DefaultColor},
+				{Name: "C", Bytes: 50, Percentage: 25, Color: languages.DefaultColor},
+				{Name: "Lua", Bytes: 50, Percentage: 25, Color: languages.DefaultColor},
+				{Name: "Zig", Bytes: 50, Percentage: 25, Color: languages.DefaultColor},
+			},
+			wantTotal: 4,
+		},
+		{
+			name: "collapses others after merging",
+			providers: []DevStats{
+				manyLanguages("A", 6, 100),
+				manyLanguages("B", 6, 50),
+				langStats(LanguageStat{Name: "B05", Bytes: 100}),
+			},
+			want: []LanguageStat{
+				{Name: "B05", Bytes: 150, Percentage: 15, Color: languages.DefaultColor},
+				{Name: "A00", Bytes: 100, Percentage: 10, Color: languages.DefaultColor},
+				{Name: "A01", Bytes: 100, Percentage: 10, Color: languages.DefaultColor},
+				{Name: "A02", Bytes: 100, Percentage: 10, Color: languages.DefaultColor},
+				{Name: "A03", Bytes: 100, Percentage: 10, Color: languages.DefaultColor},
+				{Name: "A04", Bytes: 100, Percentage: 10, Color: languages.DefaultColor},
+				{Name: "A05", Bytes: 100, Percentage: 10, Color: languages.DefaultColor},
+				{Name: "B00", Bytes: 50, Percentage: 5, Color: languages.DefaultColor},
+				{Name: "B01", Bytes: 50, Percentage: 5, Color: languages.DefaultColor},
+				{Name: "Others", Bytes: 150, Percentage: 15, Color: languages.DefaultColor},
+			},
+			wantTotal: 12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, perm := range permutations(len(tt.providers)) {
+				stats := tt.providers[perm[0]]
+				for _, i := range perm[1:] {
+					stats = MergeStats(stats, tt.providers[i])
+				}
+				stats = Finalize(stats, nil)
+
+				if !reflect.DeepEqual(stats.Activity.TopLanguages, tt.want) {
+					t.Errorf("order %v: languages = %+v, want %+v", perm, stats.Activity.TopLanguages, tt.want)
+				}
+				if stats.Totals.TotalLanguages != tt.wantTotal {
+					t.Errorf("order %v: TotalLanguages = %d, want %d", perm, stats.Totals.TotalLanguages, tt.wantTotal)
+				}
+			}
+		})
+	}
+}
+
+func TestFinalizeEmpty(t *testing.T) {
+	stats := Finalize(DevStats{}, nil)
+	if stats.Activity.TopLanguages != nil || stats.Totals.TotalLanguages != 0 {
+		t.Errorf("Finalize(empty) = %+v, %d, want no languages", stats.Activity.TopLanguages, stats.Totals.TotalLanguages)
+	}
+}
+
+func TestFinalizeExcludesLanguages(t *testing.T) {
+	stats := langStats(
+		LanguageStat{Name: "Go", Bytes: 300},
+		LanguageStat{Name: "JSON", Bytes: 500},
+		LanguageStat{Name: "HTML", Bytes: 100},
+	)
+
+	exclude := func(name string) bool { return name == "JSON" }
+	got := Finalize(stats, exclude)
+
+	want := []LanguageStat{
+		{Name: "Go", Bytes: 300, Percentage: 75, Color: languages.DefaultColor},
+		{Name: "HTML", Bytes: 100, Percentage: 25, Color: languages.DefaultColor},
+	}
+	if !reflect.DeepEqual(got.Activity.TopLanguages, want) {
+		t.Errorf("languages = %+v, want %+v", got.Activity.TopLanguages, want)
+	}
+	if got.Totals.TotalLanguages != 2 {
+		t.Errorf("TotalLanguages = %d, want 2", got.Totals.TotalLanguages)
+	}
+}
+
+func repoStats(repos ...Repository) DevStats {
+	var stats DevStats
+	for _, r := range repos {
+		if r.Private {
+			stats.Totals.PrivateRepos++
+		} else {
+			stats.Totals.PublicRepos++
+		}
+		stats.Totals.Stars += r.Stars
+		stats.Activity.TopLanguages = append(stats.Activity.TopLanguages, r.Languages...)
+	}
+	stats.Repositories = repos
+	return stats
+}
+
+func TestMergeStatsCountsMirroredReposOnce(t *testing.T) {
+	providers := []DevStats{
+		repoStats(
+			Repository{
+				Provider:  "github",
+				Name:      "ana/tool",
+				URL:       "https://github.com/ana/tool",
+				Stars:     10,
+				Languages: []LanguageStat{{Name: "Go", Bytes: 700}},
+			},
+			Repository{
+				Provider:  "github",
+				Name:      "ana/lib",
+				URL:       "https://github.com/ana/lib",
+				Stars:     5,
+				HeadSHA:   "ABC123",
+				Languages: []LanguageStat{{Name: "Rust", Bytes: 300}},
+			},
+		),
+		repoStats(
+			Repository{
+				Provider:  "gitlab",
+				Name:      "ana/tool",
+				URL:       "https://gitlab.com/ana/tool",
+				Stars:     1,
+				Source:    "git@github.com:ana/tool.git",
+				Languages: []LanguageStat{{Name: "Go", Bytes: 650}},
+			},
+			Repository{
+				Provider:  "gitlab",
+				Name:      "ana/lib",
+				URL:       "https://gitlab.com/ana/lib",
+				Stars:     2,
+				HeadSHA:   "abc123",
+				Languages: []LanguageStat{{Name: "Rust", Bytes: 310}},
+			},
+			Repository{
+				Provider:  "gitlab",
+				Name:      "ana/notes",
+				URL:       "https://gitlab.com/ana/notes",
+				Private:   true,
+				Languages: []LanguageStat{{Name: "Go", Bytes: 200}},
+			},
+		),
+		repoStats(
+			Repository{
+				Provider:  "codeberg.org",
+				Name:      "ana/tool",
+				URL:       "https://codeberg.org/ana/tool",
+				Stars:     3,
+				Source:    "https://github.com/ana/tool",
+				Languages: []LanguageStat{{Name: "Go", Bytes: 700}},
+			},
+		),
+	}
+
+	wantRepos := []string{"github:ana/lib", "github:ana/tool", "gitlab:ana/notes"}
+	wantLanguages := []LanguageStat{
+		{Name: "Go", Bytes: 900, Percentage: 75, Color: languages.DefaultColor},
+		{Name: "Rust", Bytes: 300, Percentage: 25, Color: languages.DefaultColor},
+	}
+
+	for _, perm := range permutations(len(providers)) {
+		stats := providers[perm[0]]
+		for _, i := range perm[1:] {
+			stats = MergeStats(stats, providers[i])
+		}
+		stats = Finalize(stats, nil)
+
+		if stats.Totals.PublicRepos != 2 || stats.Totals.PrivateRepos != 1 {
+			t.Errorf("order %v: repos = %d public, %d private, want 2, 1", perm, stats.Totals.PublicRepos, stats.Totals.PrivateRepos)
+		}
+		if stats.Totals.Stars != 15 {
+			t.Errorf("order %v: Stars = %d, want 15", perm, stats.Totals.Stars)
+		}
+		if stats.Totals.DuplicateRepos != 3 {
+			t.Errorf("order %v: DuplicateRepos = %d, want 3", perm, stats.Totals.DuplicateRepos)
+		}
+
+		var repos []string
+		for _, r := range stats.Repositories {
+			repos = append(repos, r.Provider+":"+r.Name)
+		}
+		if !reflect.DeepEqual(repos, wantRepos) {
+			t.Errorf("order %v: repositories = %v, want %v", perm, repos, wantRepos)
+		}
+		if !reflect.DeepEqual(stats.Activity.TopLanguages, wantLanguages) {
+			t.Errorf("order %v: languages = %+v, want %+v", perm, stats.Activity.TopLanguages, wantLanguages)
+		}
+	}
+}
+
+func TestMergeStatsRepositoryIdentity(t *testing.T) {
+	tests := []struct {
+		name           string
+		a, b           Repository
+		wantRepos      []string
+		wantDuplicates int
+	}{
+		{
+			name:           "same label on different hosts",
+			a:              Repository{Provider: "bitbucket", Name: "ana/tool", URL: "https://bitbucket.org/ana/tool"},
+			b:              Repository{Provider: "bitbucket", Name: "ana/tool", URL: "https://git.example.com/ana/tool", Source: "https://bitbucket.org/ana/tool.git"},
+			wantRepos:      []string{"bitbucket:https://bitbucket.org/ana/tool"},
+			wantDuplicates: 1,
+		},
+		{
+			name:      "same host is never merged",
+			a:         Repository{Provider: "github", Name: "ana/tool", URL: "https://github.com/ana/tool", HeadSHA: "abc"},
+			b:         Repository{Provider: "github-work", Name: "ana/tool-fork", URL: "https://github.com/ana/tool-fork", HeadSHA: "abc", Source: "https://github.com/ana/tool"},
+			wantRepos: []string{"github:https://github.com/ana/tool", "github-work:https://github.com/ana/tool-fork"},
+		},
+		{
+			name:      "forks of one upstream on different hosts",
+			a:         Repository{Provider: "github", Name: "ana/linux", URL: "https://github.com/ana/linux", Source: "https://git.kernel.org/torvalds/linux"},
+			b:         Repository{Provider: "codeberg.org", Name: "ana/linux", URL: "https://codeberg.org/ana/linux", Source: "https://git.kernel.org/torvalds/linux"},
+			wantRepos: []string{"codeberg.org:https://codeberg.org/ana/linux", "github:https://github.com/ana/linux"},
+		},
+		{
+			name:           "same head commit on different hosts",
+			a:              Repository{Provider: "github", Name: "ana/lib", URL: "https://github.com/ana/lib", HeadSHA: "ABC"},
+			b:              Repository{Provider: "gitlab", Name: "ana/lib", URL: "https://gitlab.com/ana/lib", HeadSHA: "abc"},
+			wantRepos:      []string{"github:https://github.com/ana/lib"},
+			wantDuplicates: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, order := range [][2]Repository{{tt.a, tt.b}, {tt.b, tt.a}} {
+				stats := MergeStats(repoStats(order[0]), repoStats(order[1]))
+
+				var repos []string
+				for _, r := range stats.Repositories {
+					repos = append(repos, r.Provider+":"+r.URL)
+				}
+				if !reflect.DeepEqual(repos, tt.wantRepos) {
+					t.Errorf("repositories = %v, want %v", repos, tt.wantRepos)
+				}
+				if stats.Totals.DuplicateRepos != tt.wantDuplicates {
+					t.Errorf("DuplicateRepos = %d, want %d", stats.Totals.DuplicateRepos, tt.wantDuplicates)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"sort"
+	"strings"
+)
+
+func mergeRepositories(a, b []Repository) ([]Repository, int) {
+	if len(a) == 0 && len(b) == 0 {
+		return nil, 0
+	}
+
+	all := make([]Repository, 0, len(a)+len(b))
+	all = append(all, a...)
+	all = append(all, b...)
+
+	parent := make([]int, len(all))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	for i := len(a); i < len(all); i++ {
+		for j := 0; j < i; j++ {
+			if repositoryHost(all[i]) != repositoryHost(all[j]) && sameRepository(all[i], all[j]) {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	groups := make(map[int][]Repository)
+	for i, r := range all {
+		root := find(i)
+		groups[root] = append(groups[root], r)
+	}
+
+	merged := make([]Repository, 0, len(groups))
+	for _, group := range groups {
+		merged = append(merged, pickRepository(group))
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return repositoryKey(merged[i]) < repositoryKey(merged[j])
+	})
+
+	return merged, len(all) - len(merged)
+}
+
+func sameRepository(a, b Repository) bool {
+	if a.HeadSHA != "" && strings.EqualFold(a.HeadSHA, b.HeadSHA) {
+		return true
+	}
+	return mirrors(a, b) || mirrors(b, a)
+}
+
+func repositoryHost(r Repository) string {
+	if host, _, _ := strings.Cut(normalizeRepoURL(r.URL), "/"); host != "" {
+		return host
+	}
+	return r.Provider
+}
+
+func pickRepository(group []Repository) Repository {
+	var best Repository
+	found := false
+	for _, r := range group {
+		if isMirrorIn(r, group) {
+			continue
+		}
+		if !found || repositoryKey(r) < repositoryKey(best) {
+			best, found = r, true
+		}
+	}
+	if found {
+		return best
+	}
+
+	best = group[0]
+	for _, r := range group[1:] {
+		if repositoryKey(r) < repositoryKey(best) {
+			best = r
+		}
+	}
+	return best
+}
+
+func isMirrorIn(r Repository, group []Repository) bool {
+	for _, other := range group {
+		if mirrors(r, other) {
+			return true
+		}
+	}
+	return false
+}
+
+func mirrors(r, of Repository) bool {
+	source := normalizeRepoURL(r.Source)
+	return source != "" && source == normalizeRepoURL(of.URL)
+}
+
+func repositoryKey(r Repository) string {
+	return r.Provider + "\x00" + r.Name
+}
+
+func normalizeRepoURL(u string) string {
+	u = strings.ToLower(strings.TrimSpace(u))
+	if u == "" {
+		return ""
+	}
+
+	scpLike := true
+	if i := strings.Index(u, "://"); i >= 0 {
+		u = u[i+3:]
+		scpLike = false
+	}
+	if at := strings.Index(u, "@"); at >= 0 {
+		if slash := strings.Index(u, "/"); slash < 0 || at < slash {
+			u = u[at+1:]
+		}
+	}
+	if scpLike {
+		u = strings.Replace(u, ":", "/", 1)
+	}
+
+	u = strings.TrimPrefix(u, "www.")
+	u = strings.TrimSuffix(u, "/")
+	return strings.TrimSuffix(u, ".git")
+}
+
+func applyRepositoryTotals(stats *DevStats) {
+	stats.Totals.PublicRepos = 0
+	stats.Totals.PrivateRepos = 0
+	stats.Totals.Stars = 0
+
+	var langs []LanguageStat
+	for _, r := range stats.Repositories {
+		if r.Private {
+			stats.Totals.PrivateRepos++
+		} else {
+			stats.Totals.PublicRepos++
+		}
+		stats.Totals.Stars += r.Stars
+		langs = append(langs, r.Languages...)
+	}
+
+	stats.Activity.TopLanguages = mergeLanguageStats(nil, langs)
+}
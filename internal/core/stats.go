@@ -11,6 +11,13 @@ type Identity struct {
 	Handles  []string
 }
 
+func DisplayName(name, login string) string {
+	if name != "" {
+		return name
+	}
+	return login
+}
+
 type Totals struct {
 	PublicRepos      int
 	PrivateRepos     int
@@ -18,20 +25,36 @@ type Totals struct {
 	Followers        int
 	Following        int
 	ContributedRepos int
-	JoinedAgo        string
+	Joined           time.Time
 	TotalLanguages   int
 	Commits          int
 	CurrentStreak    int
 	LongestStreak    int
 	CommitsThisWeek  int
+	Reviews          int
+	FilteredRepos    int
+	DuplicateRepos   int
 }
 
 type LanguageStat struct {
 	Name       string
+	Bytes      int64
+	Lines      int64
 	Percentage float64
 	Color      string
 }
 
+type Repository struct {
+	Provider  string
+	Name      string
+	URL       string
+	Private   bool
+	Stars     int
+	Source    string
+	HeadSHA   string
+	Languages []LanguageStat
+}
+
 type IssueStats struct {
 	Open   int
 	Closed int
@@ -43,6 +66,19 @@ type PRStats struct {
 	Closed int
 }
 
+type PackageStat struct {
+	Name             string
+	Registry         string
+	Downloads        int64
+	DownloadsUnknown bool
+}
+
+type Packages struct {
+	Count          int
+	TotalDownloads int64
+	Top            []PackageStat
+}
+
 type Activity struct {
 	ContributionsPerDay map[time.Time]int
 	TopLanguages        []LanguageStat
@@ -51,7 +87,10 @@ type Activity struct {
 }
 
 type DevStats struct {
-	Identity Identity
-	Totals   Totals
-	Activity Activity
+	Identity     Identity
+	Totals       Totals
+	Activity     Activity
+	Packages     Packages
+	Repositories []Repository
+	Diagnostics  Diagnostics
 }
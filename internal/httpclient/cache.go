@@ -0,0 +1,263 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTTL    = 30 * time.Minute
+	maxCachedBody = 10 << 20
+	cacheDirName  = "devmetrics"
+)
+
+var identityHeaders = []string{"Authorization", "Private-Token", "Cookie"}
+
+var ttlRules = []struct {
+	match func(u *url.URL) bool
+	ttl   time.Duration
+}{
+	{func(u *url.URL) bool { return strings.Contains(u.Path, "/search/") }, 15 * time.Minute},
+	{func(u *url.URL) bool {
+		return strings.HasSuffix(u.Path, "/events") || strings.HasSuffix(u.Path, "/heatmap") || strings.Contains(u.Path, "/commits")
+	}, 15 * time.Minute},
+	{func(u *url.URL) bool { return strings.Contains(u.Host, "avatar") || strings.Contains(u.Path, "avatar") }, 24 * time.Hour},
+	{func(u *url.URL) bool { return strings.HasSuffix(u.Path, "/languages") }, 6 * time.Hour},
+}
+
+var (
+	cacheMu      sync.Mutex
+	defaultCache *Cache
+)
+
+type Cache struct {
+	dir      string
+	warnOnce sync.Once
+}
+
+type cacheEntry struct {
+	URL      string      `json:"url"`
+	StoredAt time.Time   `json:"stored_at"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+}
+
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+func DefaultCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("httpclient: locate user cache dir: %w", err)
+	}
+	return filepath.Join(base, cacheDirName), nil
+}
+
+func SetCache(c *Cache) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	defaultCache = c
+}
+
+func currentCache() *Cache {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	return defaultCache
+}
+
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+func (c *Cache) Clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("httpclient: clear cache %s: %w", c.dir, err)
+	}
+	return nil
+}
+
+func (c *Cache) Wrap(next http.RoundTripper) http.RoundTripper {
+	return &cacheTransport{cache: c, next: next}
+}
+
+type cacheTransport struct {
+	cache *Cache
+	next  http.RoundTripper
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	entry, _ := t.cache.load(key)
+
+	if entry != nil && time.Since(entry.StoredAt) < ttlFor(req.URL) {
+		return entry.response(req), nil
+	}
+
+	out := req
+	if entry != nil {
+		out = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			out.Header.Set("If-None-Match", etag)
+		}
+		if modified := entry.Header.Get("Last-Modified"); modified != "" {
+			out.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		entry.StoredAt = time.Now()
+		t.cache.store(key, entry)
+		return entry.response(req), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || noStore(resp.Header) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBody+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedBody {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+
+	t.cache.store(key, &cacheEntry{
+		URL:      req.URL.String(),
+		StoredAt: time.Now(),
+		Status:   resp.StatusCode,
+		Header:   header,
+		Body:     body,
+	})
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+func (c *Cache) load(key string) (*cacheEntry, error) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, err
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (c *Cache) store(key string, entry *cacheEntry) {
+	if err := c.write(key, entry); err != nil {
+		c.warnOnce.Do(func() {
+			log.Printf("httpclient: write cache entry: %v", err)
+		})
+	}
+}
+
+func (c *Cache) write(key string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func cacheKey(req *http.Request) string {
+	h := sha256.New()
+	io.WriteString(h, req.URL.String())
+	io.WriteString(h, "\x00"+req.Header.Get("Accept"))
+	for _, name := range identityHeaders {
+		io.WriteString(h, "\x00"+req.Header.Get(name))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func ttlFor(u *url.URL) time.Duration {
+	for _, rule := range ttlRules {
+		if rule.match(u) {
+			return rule.ttl
+		}
+	}
+	return defaultTTL
+}
+
+func noStore(h http.Header) bool {
+	for _, directive := range strings.Split(h.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,253 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type cacheServer struct {
+	*httptest.Server
+	calls   atomic.Int32
+	body    atomic.Value
+	lastReq atomic.Pointer[http.Request]
+}
+
+func newCacheServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, body string)) *cacheServer {
+	t.Helper()
+	s := &cacheServer{}
+	s.body.Store("v1")
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.calls.Add(1)
+		s.lastReq.Store(r.Clone(r.Context()))
+		handler(w, r, s.body.Load().(string))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func cachedClient(t *testing.T) (*http.Client, *Cache) {
+	t.Helper()
+	c := NewCache(t.TempDir())
+	return &http.Client{Transport: c.Wrap(http.DefaultTransport)}, c
+}
+
+func fetch(t *testing.T, client *http.Client, method, target string, header http.Header) string {
+	t.Helper()
+	req, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, target, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func expire(t *testing.T, c *Cache, target string, header http.Header) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, target, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	key := cacheKey(req)
+	entry, err := c.load(key)
+	if err != nil {
+		t.Fatalf("load cache entry: %v", err)
+	}
+	entry.StoredAt = time.Now().Add(-48 * time.Hour)
+	c.store(key, entry)
+}
+
+func TestCacheServesFreshEntries(t *testing.T) {
+	srv := newCacheServer(t, func(w http.ResponseWriter, r *http.Request, body string) {
+		io.WriteString(w, body)
+	})
+	client, _ := cachedClient(t)
+
+	first := fetch(t, client, http.MethodGet, srv.URL+"/users/ada", nil)
+	srv.body.Store("v2")
+	second := fetch(t, client, http.MethodGet, srv.URL+"/users/ada", nil)
+
+	if first != "v1" || second != "v1" {
+		t.Errorf("bodies = %q, %q, want the cached v1 twice", first, second)
+	}
+	if got := srv.calls.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	srv := newCacheServer(t, func(w http.ResponseWriter, r *http.Request, body string) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		io.WriteString(w, body)
+	})
+	client, c := cachedClient(t)
+	target := srv.URL + "/users/ada"
+
+	fetch(t, client, http.MethodGet, target, nil)
+	expire(t, c, target, nil)
+
+	if got := fetch(t, client, http.MethodGet, target, nil); got != "v1" {
+		t.Errorf("body after 304 = %q, want cached v1", got)
+	}
+	if got := srv.calls.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+
+	fetch(t, client, http.MethodGet, target, nil)
+	if got := srv.calls.Load(); got != 2 {
+		t.Errorf("requests after 304 = %d, want 2 because the entry is fresh again", got)
+	}
+}
+
+func TestCacheRefetchesExpiredEntries(t *testing.T) {
+	modified := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+	srv := newCacheServer(t, func(w http.ResponseWriter, r *http.Request, body string) {
+		w.Header().Set("Last-Modified", modified)
+		io.WriteString(w, body)
+	})
+	client, c := cachedClient(t)
+	target := srv.URL + "/repos/ada/engine/languages"
+
+	fetch(t, client, http.MethodGet, target, nil)
+	expire(t, c, target, nil)
+	srv.body.Store("v2")
+
+	if got := fetch(t, client, http.MethodGet, target, nil); got != "v2" {
+		t.Errorf("body after expiry = %q, want v2", got)
+	}
+	if got := srv.lastReq.Load().Header.Get("If-Modified-Since"); got != modified {
+		t.Errorf("If-Modified-Since = %q, want %q", got, modified)
+	}
+	if got := fetch(t, client, http.MethodGet, target, nil); got != "v2" {
+		t.Errorf("body after refetch = %q, want the stored v2", got)
+	}
+	if got := srv.calls.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestCacheSkipsNonGetRequests(t *testing.T) {
+	srv := newCacheServer(t, func(w http.ResponseWriter, r *http.Request, body string) {
+		io.WriteString(w, body)
+	})
+	client, _ := cachedClient(t)
+
+	fetch(t, client, http.MethodPost, srv.URL+"/graphql", nil)
+	fetch(t, client, http.MethodPost, srv.URL+"/graphql", nil)
+
+	if got := srv.calls.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestCacheSkipsNoStore(t *testing.T) {
+	srv := newCacheServer(t, func(w http.ResponseWriter, r *http.Request, body string) {
+		w.Header().Set("Cache-Control", "private, no-store")
+		io.WriteString(w, body)
+	})
+	client, _ := cachedClient(t)
+
+	fetch(t, client, http.MethodGet, srv.URL+"/user", nil)
+	fetch(t, client, http.MethodGet, srv.URL+"/user", nil)
+
+	if got := srv.calls.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestCacheSeparatesCredentials(t *testing.T) {
+	srv := newCacheServer(t, func(w http.ResponseWriter, r *http.Request, body string) {
+		io.WriteString(w, "private data for "+r.Header.Get("Authorization"))
+	})
+	client, _ := cachedClient(t)
+	target := srv.URL + "/user/repos"
+
+	alice := http.Header{"Authorization": {"Bearer alice"}}
+	bob := http.Header{"Authorization": {"Bearer bob"}}
+
+	fetch(t, client, http.MethodGet, target, alice)
+	if got := fetch(t, client, http.MethodGet, target, bob); !strings.HasSuffix(got, "bob") {
+		t.Errorf("bob got %q, want his own response", got)
+	}
+	if got := fetch(t, client, http.MethodGet, target, alice); !strings.HasSuffix(got, "alice") {
+		t.Errorf("alice got %q, want her cached response", got)
+	}
+	if got := srv.calls.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestCacheKeyIncludesIdentityHeaders(t *testing.T) {
+	key := func(header http.Header) string {
+		req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/user", nil)
+		req.Header = header
+		return cacheKey(req)
+	}
+
+	base := key(http.Header{})
+	seen := map[string]http.Header{base: {}}
+	for _, header := range []http.Header{
+		{"Authorization": {"token a"}},
+		{"Authorization": {"token b"}},
+		{"Private-Token": {"token a"}},
+		{"Cookie": {"session=a"}},
+		{"Accept": {"application/vnd.github+json"}},
+	} {
+		k := key(header)
+		if prev, ok := seen[k]; ok {
+			t.Errorf("key for %v equals key for %v", header, prev)
+		}
+		seen[k] = header
+	}
+
+	if key(http.Header{"User-Agent": {"devmetrics"}}) != base {
+		t.Errorf("User-Agent changed the cache key")
+	}
+}
+
+func TestTTLFor(t *testing.T) {
+	tests := []struct {
+		url  string
+		want time.Duration
+	}{
+		{"https://api.github.com/search/issues?q=author:ada", 15 * time.Minute},
+		{"https://api.github.com/users/ada/events", 15 * time.Minute},
+		{"https://gitlab.com/api/v4/projects/1/repository/commits", 15 * time.Minute},
+		{"https://avatars.githubusercontent.com/u/1", 24 * time.Hour},
+		{"https://api.github.com/repos/ada/engine/languages", 6 * time.Hour},
+		{"https://api.github.com/users/ada", defaultTTL},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ttlFor(u); got != tt.want {
+			t.Errorf("ttlFor(%s) = %s, want %s", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package httpclient
+
+import (
+	"net/url"
+	"strings"
+)
+
+func HostLabel(baseURL, fallback string, known map[string]string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Hostname() == "" {
+		return fallback
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if label, ok := known[host]; ok {
+		return label
+	}
+	return host
+}
+
+func APIURL(baseURL, apiPath string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if strings.HasSuffix(baseURL, apiPath) {
+		return baseURL
+	}
+	return baseURL + apiPath
+}
@@ -0,0 +1,41 @@
+package httpclient
+
+import "testing"
+
+func TestHostLabel(t *testing.T) {
+	known := map[string]string{"codeberg.org": "codeberg"}
+
+	tests := []struct {
+		baseURL string
+		want    string
+	}{
+		{"https://codeberg.org", "codeberg"},
+		{"https://Codeberg.org/", "codeberg"},
+		{"https://git.example.com:3000", "git.example.com"},
+		{"", "gitea"},
+		{"::not a url", "gitea"},
+	}
+
+	for _, tt := range tests {
+		if got := HostLabel(tt.baseURL, "gitea", known); got != tt.want {
+			t.Errorf("HostLabel(%q) = %q, want %q", tt.baseURL, got, tt.want)
+		}
+	}
+}
+
+func TestAPIURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+	}{
+		{"https://gitlab.com", "https://gitlab.com/api/v4"},
+		{"https://gitlab.com/", "https://gitlab.com/api/v4"},
+		{"https://gitlab.com/api/v4", "https://gitlab.com/api/v4"},
+	}
+
+	for _, tt := range tests {
+		if got := APIURL(tt.baseURL, "/api/v4"); got != tt.want {
+			t.Errorf("APIURL(%q) = %q, want %q", tt.baseURL, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAttemptTimeout = 10 * time.Second
+	defaultMaxRetries     = 3
+	defaultBaseDelay      = 500 * time.Millisecond
+	defaultMaxDelay       = 8 * time.Second
+	defaultMaxWait        = 60 * time.Second
+)
+
+type RateLimitError struct {
+	Host   string
+	Status int
+	Reset  time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Status == 0 {
+		return fmt.Sprintf("rate limit exhausted for %s, resets at %s", e.Host, e.Reset.Format(time.RFC3339))
+	}
+	if e.Reset.IsZero() {
+		return fmt.Sprintf("rate limit exceeded for %s (status %d)", e.Host, e.Status)
+	}
+	return fmt.Sprintf("rate limit exceeded for %s (status %d), resets at %s", e.Host, e.Status, e.Reset.Format(time.RFC3339))
+}
+
+type Transport struct {
+	Base           http.RoundTripper
+	AttemptTimeout time.Duration
+	MaxRetries     int
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	MaxWait        time.Duration
+
+	mu        sync.Mutex
+	exhausted map[string]budget
+}
+
+type budget struct {
+	until time.Time
+	reset time.Time
+}
+
+func New() *http.Client {
+	var rt http.RoundTripper = NewTransport(http.DefaultTransport)
+	if c := currentCache(); c != nil {
+		rt = c.Wrap(rt)
+	}
+	return &http.Client{Transport: rt}
+}
+
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{
+		Base:           base,
+		AttemptTimeout: defaultAttemptTimeout,
+		MaxRetries:     defaultMaxRetries,
+		BaseDelay:      defaultBaseDelay,
+		MaxDelay:       defaultMaxDelay,
+		MaxWait:        defaultMaxWait,
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if err := t.waitForBudget(req); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		canRetry := attempt < t.MaxRetries && replayable(req)
+
+		resp, err := t.attempt(req, attempt)
+		if err != nil {
+			if ctx.Err() != nil || !canRetry {
+				return nil, err
+			}
+			if err := sleep(ctx, t.backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		now := time.Now()
+
+		if isRateLimited(resp) {
+			wait, reset := rateLimitWait(resp.Header, now)
+			if wait <= 0 {
+				wait = t.backoff(attempt)
+			}
+
+			if !canRetry || wait > t.MaxWait || !fitsDeadline(ctx, wait) {
+				discard(resp)
+				return nil, &RateLimitError{Host: req.URL.Host, Status: resp.StatusCode, Reset: reset}
+			}
+
+			log.Printf("httpclient: rate limited by %s, retrying in %s", req.URL.Host, wait.Round(time.Second))
+			discard(resp)
+			if err := sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if isTransient(resp.StatusCode) && canRetry {
+			wait := retryAfter(resp.Header, now)
+			if wait <= 0 || wait > t.MaxDelay {
+				wait = t.backoff(attempt)
+			}
+			if fitsDeadline(ctx, wait) {
+				discard(resp)
+				if err := sleep(ctx, wait); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+
+		if resp.StatusCode < 300 && budgetSpent(resp.Header) {
+			t.markExhausted(req.URL.Host, resp.Header, now)
+		}
+
+		return resp, nil
+	}
+}
+
+func (t *Transport) waitForBudget(req *http.Request) error {
+	t.mu.Lock()
+	b, ok := t.exhausted[req.URL.Host]
+	t.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	wait := time.Until(b.until)
+	if wait <= 0 {
+		return nil
+	}
+	if wait > t.MaxWait || !fitsDeadline(req.Context(), wait) {
+		return &RateLimitError{Host: req.URL.Host, Reset: b.reset}
+	}
+
+	log.Printf("httpclient: rate limit for %s used up, waiting %s", req.URL.Host, wait.Round(time.Second))
+	return sleep(req.Context(), wait)
+}
+
+func (t *Transport) markExhausted(host string, h http.Header, now time.Time) {
+	wait, reset := rateLimitWait(h, now)
+	if wait <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.exhausted == nil {
+		t.exhausted = make(map[string]budget)
+	}
+	t.exhausted[host] = budget{until: now.Add(wait), reset: reset}
+}
+
+func (t *Transport) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if t.AttemptTimeout > 0 {
+		ctx, cancel = context.WithTimeout(req.Context(), t.AttemptTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(req.Context())
+	}
+
+	out := req.Clone(ctx)
+	if attempt > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("httpclient: rewind request body: %w", err)
+		}
+		out.Body = body
+	}
+
+	resp, err := t.Base.RoundTrip(out)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+func (t *Transport) backoff(attempt int) time.Duration {
+	d := t.BaseDelay << attempt
+	if d <= 0 || d > t.MaxDelay {
+		d = t.MaxDelay
+	}
+
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+func isRateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get("Retry-After") != "" || budgetSpent(resp.Header)
+	}
+	return false
+}
+
+func budgetSpent(h http.Header) bool {
+	return h.Get("X-RateLimit-Remaining") == "0" || h.Get("RateLimit-Remaining") == "0"
+}
+
+func isTransient(status int) bool {
+	switch status {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func rateLimitWait(h http.Header, now time.Time) (time.Duration, time.Time) {
+	if wait := retryAfter(h, now); wait > 0 {
+		return wait, now.Add(wait)
+	}
+
+	for _, name := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+		secs, err := strconv.ParseInt(h.Get(name), 10, 64)
+		if err != nil || secs <= 0 {
+			continue
+		}
+
+		reset := time.Unix(secs, 0)
+		if wait := reset.Sub(now) + time.Second; wait > 0 {
+			return wait, reset
+		}
+		return 0, reset
+	}
+
+	return 0, time.Time{}
+}
+
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	value := h.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t.Sub(now)
+	}
+
+	return 0
+}
+
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func fitsDeadline(ctx context.Context, wait time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > wait
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func discard(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,249 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testClient(maxRetries int) *http.Client {
+	t := NewTransport(http.DefaultTransport)
+	t.MaxRetries = maxRetries
+	t.BaseDelay = time.Millisecond
+	t.MaxDelay = 5 * time.Millisecond
+	t.MaxWait = 5 * time.Second
+	return &http.Client{Transport: t}
+}
+
+func serve(t *testing.T, handler func(w http.ResponseWriter, attempt int)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, int(calls.Add(1)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestRoundTripWaitsForRetryAfter(t *testing.T) {
+	srv, calls := serve(t, func(w http.ResponseWriter, attempt int) {
+		if attempt == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	start := time.Now()
+	resp, err := testClient(3).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the 1s Retry-After", elapsed)
+	}
+}
+
+func TestRoundTripRateLimitErrorPastURLError(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	srv, calls := serve(t, func(w http.ResponseWriter, attempt int) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	_, err := testClient(3).Get(srv.URL)
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Fatalf("error = %v, want *url.Error", err)
+	}
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("error = %v, want *RateLimitError", err)
+	}
+	if rateErr.Status != http.StatusForbidden || !rateErr.Reset.Equal(reset) {
+		t.Errorf("RateLimitError = %+v, want status 403 resetting at %s", rateErr, reset)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1 when the reset is beyond MaxWait", got)
+	}
+}
+
+func TestRoundTripWaitsForSpentBudget(t *testing.T) {
+	var reset time.Time
+	srv, calls := serve(t, func(w http.ResponseWriter, attempt int) {
+		if attempt == 1 {
+			reset = time.Now().Add(time.Second).Truncate(time.Second)
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	client := testClient(3)
+	for i := range 2 {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("Get %d: %v", i+1, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Get %d status = %d, want 200", i+1, resp.StatusCode)
+		}
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+	if time.Now().Before(reset) {
+		t.Errorf("second request went out before the reset at %s", reset)
+	}
+}
+
+func TestRoundTripSpentBudgetPastMaxWait(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	srv, calls := serve(t, func(w http.ResponseWriter, attempt int) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	client := testClient(3)
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	_, err = client.Get(srv.URL)
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("error = %v, want *RateLimitError", err)
+	}
+	if !rateErr.Reset.Equal(reset) {
+		t.Errorf("RateLimitError = %+v, want resetting at %s", rateErr, reset)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1 once the budget is spent", got)
+	}
+}
+
+func TestRoundTripForbiddenWithoutRateLimit(t *testing.T) {
+	srv, calls := serve(t, func(w http.ResponseWriter, attempt int) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	resp, err := testClient(3).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusForbidden || calls.Load() != 1 {
+		t.Errorf("status = %d after %d requests, want 403 after 1", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRoundTripExhaustsRetriesOnServerErrors(t *testing.T) {
+	srv, calls := serve(t, func(w http.ResponseWriter, attempt int) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	resp, err := testClient(2).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("requests = %d, want 3 (1 + 2 retries)", got)
+	}
+}
+
+func TestRoundTripRecoversFromServerError(t *testing.T) {
+	srv, calls := serve(t, func(w http.ResponseWriter, attempt int) {
+		if attempt < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	resp, err := testClient(3).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Errorf("status = %d after %d requests, want 200 after 3", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		header    http.Header
+		wantWait  time.Duration
+		wantReset time.Time
+	}{
+		{
+			name:      "retry after seconds",
+			header:    http.Header{"Retry-After": {"30"}},
+			wantWait:  30 * time.Second,
+			wantReset: now.Add(30 * time.Second),
+		},
+		{
+			name:      "retry after date",
+			header:    http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}},
+			wantWait:  time.Minute,
+			wantReset: now.Add(time.Minute),
+		},
+		{
+			name:      "reset timestamp",
+			header:    http.Header{"X-Ratelimit-Reset": {strconv.FormatInt(now.Add(10*time.Second).Unix(), 10)}},
+			wantWait:  11 * time.Second,
+			wantReset: now.Add(10 * time.Second),
+		},
+		{
+			name:      "reset in the past",
+			header:    http.Header{"Ratelimit-Reset": {strconv.FormatInt(now.Add(-time.Minute).Unix(), 10)}},
+			wantReset: now.Add(-time.Minute),
+		},
+		{
+			name:   "no headers",
+			header: http.Header{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, reset := rateLimitWait(tt.header, now)
+			if wait != tt.wantWait || !reset.Equal(tt.wantReset) {
+				t.Errorf("rateLimitWait = %s, %s, want %s, %s", wait, reset, tt.wantWait, tt.wantReset)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package languages
+
+import (
+	"fmt"
+	"strings"
+)
+
+const DefaultColor = "#586069"
+
+type Type string
+
+const (
+	Programming Type = "programming"
+	Markup      Type = "markup"
+	Data        Type = "data"
+	Prose       Type = "prose"
+)
+
+type Language struct {
+	Name    string
+	Type    Type
+	Color   string
+	Aliases []string
+}
+
+var index = buildIndex()
+
+func buildIndex() map[string]Language {
+	m := make(map[string]Language, len(registry)*2)
+	for _, l := range registry {
+		m[key(l.Name)] = l
+		for _, a := range l.Aliases {
+			m[key(a)] = l
+		}
+	}
+	return m
+}
+
+func key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func Lookup(name string) (Language, bool) {
+	l, ok := index[key(name)]
+	return l, ok
+}
+
+func Normalize(name string) string {
+	if l, ok := Lookup(name); ok {
+		return l.Name
+	}
+	return strings.TrimSpace(name)
+}
+
+func Color(name string) string {
+	if l, ok := Lookup(name); ok && l.Color != "" {
+		return l.Color
+	}
+	return DefaultColor
+}
+
+func ParseTypes(list []string) ([]Type, error) {
+	types := make([]Type, 0, len(list))
+	for _, s := range list {
+		t := Type(key(s))
+		switch t {
+		case Programming, Markup, Data, Prose:
+			types = append(types, t)
+		default:
+			return nil, fmt.Errorf("languages: unknown language type %q (want programming, markup, data or prose)", s)
+		}
+	}
+	return types, nil
+}
+
+func ExcludeTypes(types ...Type) func(name string) bool {
+	if len(types) == 0 {
+		return nil
+	}
+
+	return func(name string) bool {
+		l, ok := Lookup(name)
+		if !ok {
+			return false
+		}
+		for _, t := range types {
+			if l.Type == t {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,96 @@
+package languages
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Go":           "Go",
+		"golang":       "Go",
+		" GoLang ":     "Go",
+		"javascript":   "JavaScript",
+		"js":           "JavaScript",
+		"bash":         "Shell",
+		"csharp":       "C#",
+		"Unknown Lang": "Unknown Lang",
+		" custom ":     "custom",
+	}
+
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestColor(t *testing.T) {
+	tests := map[string]string{
+		"Go":         "#00ADD8",
+		"golang":     "#00ADD8",
+		"HTML":       "#e34c26",
+		"Whitespace": DefaultColor,
+		"":           DefaultColor,
+	}
+
+	for in, want := range tests {
+		if got := Color(in); got != want {
+			t.Errorf("Color(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseTypes(t *testing.T) {
+	tests := []struct {
+		in      []string
+		want    []Type
+		wantErr string
+	}{
+		{in: nil, want: []Type{}},
+		{in: []string{"markup", " Data ", "PROSE"}, want: []Type{Markup, Data, Prose}},
+		{in: []string{"programming"}, want: []Type{Programming}},
+		{in: []string{"markup", "config"}, wantErr: `unknown language type "config"`},
+		{in: []string{""}, wantErr: `unknown language type ""`},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTypes(tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseTypes(%q) error = %v, want containing %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTypes(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTypes(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExcludeTypes(t *testing.T) {
+	if ExcludeTypes() != nil {
+		t.Errorf("ExcludeTypes() without types should return nil")
+	}
+
+	exclude := ExcludeTypes(Markup, Data)
+	tests := map[string]bool{
+		"Go":       false,
+		"HTML":     true,
+		"xhtml":    true,
+		"JSON":     true,
+		"Markdown": false,
+		"Mystery":  false,
+	}
+
+	for name, want := range tests {
+		if got := exclude(name); got != want {
+			t.Errorf("exclude(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package languages
+
+// Source: https://github.com/github/linguist/blob/master/lib/linguist/languages.yml
+var registry = []Language{
+	{Name: "1C Enterprise", Type: Programming, Color: "#814CCC"},
+	{Name: "ABAP", Type: Programming, Color: "#E82C0C"},
+	{Name: "ActionScript", Type: Programming, Color: "#882B0F"},
+	{Name: "Ada", Type: Programming, Color: "#02f88c"},
+	{Name: "Agda", Type: Programming, Color: "#315665"},
+	{Name: "AGS Script", Type: Programming, Color: "#B9D9FF"},
+	{Name: "Alloy", Type: Programming, Color: "#64C800"},
+	{Name: "Alpine Abuild", Type: Programming, Color: "#0D597F"},
+	{Name: "AMPL", Type: Programming, Color: "#E6EFBB"},
+	{Name: "AngelScript", Type: Programming, Color: "#C7D7DC"},
+	{Name: "ANTLR", Type: Programming, Color: "#9DC3FF"},
+	{Name: "Apex", Type: Programming, Color: "#1797c0"},
+	{Name: "API Blueprint", Type: Markup, Color: "#2ACCA8"},
+	{Name: "APL", Type: Programming, Color: "#5A8164"},
+	{Name: "Apollo Guidance Computer", Type: Programming, Color: "#0B3D91"},
+	{Name: "AppleScript", Type: Programming, Color: "#101F1F"},
+	{Name: "Arc", Type: Programming, Color: "#aa2afe"},
+	{Name: "Arduino", Type: Programming, Color: "#bd79d1"},
+	{Name: "AsciiDoc", Type: Prose, Color: "#73a0c5", Aliases: []string{"adoc"}},
+	{Name: "ASP.NET", Type: Programming, Color: "#9400ff", Aliases: []string{"aspx", "aspx-vb"}},
+	{Name: "Assembly", Type: Programming, Color: "#6E4C13", Aliases: []string{"asm", "nasm"}},
+	{Name: "Astro", Type: Markup, Color: "#ff5a03"},
+	{Name: "AutoHotkey", Type: Programming, Color: "#6594b9"},
+	{Name: "AutoIt", Type: Programming, Color: "#1C3552"},
+	{Name: "Awk", Type: Programming, Color: "#c30e9b"},
+	{Name: "Ballerina", Type: Programming, Color: "#FF5000"},
+	{Name: "Batchfile", Type: Programming, Color: "#C1F12E", Aliases: []string{"bat", "batch", "dosbatch", "winbatch"}},
+	{Name: "Blade", Type: Markup, Color: "#f7523f"},
+	{Name: "BlitzMax", Type: Programming, Color: "#cd6400"},
+	{Name: "Boo", Type: Programming, Color: "#d4bec1"},
+	{Name: "Brainfuck", Type: Programming, Color: "#2F2530"},
+	{Name: "C", Type: Programming, Color: "#555555"},
+	{Name: "C#", Type: Programming, Color: "#178600", Aliases: []string{"csharp", "cake", "cakescript"}},
+	{Name: "C++", Type: Programming, Color: "#f34b7d", Aliases: []string{"cpp"}},
+	{Name: "C2hs Haskell", Type: Programming, Color: "#53b7a6"},
+	{Name: "Cap'n Proto", Type: Programming, Color: "#c42727"},
+	{Name: "CartoCSS", Type: Programming, Color: "#613361"},
+	{Name: "Ceylon", Type: Programming, Color: "#dfa535"},
+	{Name: "Chapel", Type: Programming, Color: "#8dc63f"},
+	{Name: "Charity", Type: Programming, Color: "#613361"},
+	{Name: "ChucK", Type: Programming, Color: "#3f8000"},
+	{Name: "Cirru", Type: Programming, Color: "#ccccff"},
+	{Name: "Clarion", Type: Programming, Color: "#db901e"},
+	{Name: "Clean", Type: Programming, Color: "#3F85AF"},
+	{Name: "Click", Type: Programming, Color: "#E4E6F3"},
+	{Name: "CLIPS", Type: Programming, Color: "#00A300"},
+	{Name: "Clojure", Type: Programming, Color: "#db5855"},
+	{Name: "CMake", Type: Programming, Color: "#DA3434"},
+	{Name: "COBOL", Type: Programming, Color: "#90a95a"},
+	{Name: "CoffeeScript", Type: Programming, Color: "#244776"},
+	{Name: "ColdFusion", Type: Programming, Color: "#ed2cd6"},
+	{Name: "Common Lisp", Type: Programming, Color: "#3fb68b", Aliases: []string{"lisp"}},
+	{Name: "Component Pascal", Type: Programming, Color: "#B0CE4E"},
+	{Name: "Cool", Type: Programming, Color: "#915568"},
+	{Name: "Coq", Type: Programming, Color: "#d0b68c"},
+	{Name: "Crystal", Type: Programming, Color: "#000100"},
+	{Name: "CSS", Type: Markup, Color: "#563d7c"},
+	{Name: "CSV", Type: Data, Color: "#237346"},
+	{Name: "Cucumber", Type: Programming, Color: "#5B2063"},
+	{Name: "Cuda", Type: Programming, Color: "#3A4E3A"},
+	{Name: "Cycript", Type: Programming, Color: "#925a51"},
+	{Name: "Cython", Type: Programming, Color: "#fedf5b"},
+	{Name: "D", Type: Programming, Color: "#ba595e"},
+	{Name: "Dart", Type: Programming, Color: "#00B4AB"},
+	{Name: "Diff", Type: Data, Color: "#88dddd"},
+	{Name: "DIGITAL Command Language", Type: Programming, Color: "#F153F5"},
+	{Name: "DM", Type: Programming, Color: "#447265"},
+	{Name: "Dockerfile", Type: Programming, Color: "#384d54", Aliases: []string{"containerfile"}},
+	{Name: "Dogescript", Type: Programming, Color: "#cca760"},
+	{Name: "DTrace", Type: Programming, Color: "#cc0000"},
+	{Name: "Dylan", Type: Programming, Color: "#6c616e"},
+	{Name: "E", Type: Programming, Color: "#ccce35"},
+	{Name: "eC", Type: Programming, Color: "#913960"},
+	{Name: "ECL", Type: Programming, Color: "#8a1267"},
+	{Name: "Eiffel", Type: Programming, Color: "#4d6977"},
+	{Name: "Elixir", Type: Programming, Color: "#6e4a7e"},
+	{Name: "Elm", Type: Programming, Color: "#60B5CC"},
+	{Name: "Emacs Lisp", Type: Programming, Color: "#c065db", Aliases: []string{"elisp", "emacs"}},
+	{Name: "EmberScript", Type: Programming, Color: "#FFF4F3"},
+	{Name: "Erlang", Type: Programming, Color: "#B83998"},
+	{Name: "F#", Type: Programming, Color: "#b845fc", Aliases: []string{"fsharp"}},
+	{Name: "Factor", Type: Programming, Color: "#636746"},
+	{Name: "Fancy", Type: Programming, Color: "#7b9db4"},
+	{Name: "Fantom", Type: Programming, Color: "#14253c"},
+	{Name: "Forth", Type: Programming, Color: "#341708"},
+	{Name: "Fortran", Type: Programming, Color: "#4d41b1"},
+	{Name: "FreeMarker", Type: Programming, Color: "#0050b2"},
+	{Name: "Frege", Type: Programming, Color: "#d7f7fa"},
+	{Name: "Game Maker Language", Type: Programming, Color: "#71b417", Aliases: []string{"gml"}},
+	{Name: "GAMS", Type: Programming, Color: "#f49a22"},
+	{Name: "GAP", Type: Programming, Color: "#0000cc"},
+	{Name: "GCC Machine Description", Type: Programming, Color: "#FFCF15"},
+	{Name: "GDB", Type: Programming, Color: "#613361"},
+	{Name: "GDScript", Type: Programming, Color: "#355570"},
+	{Name: "Genie", Type: Programming, Color: "#fb855d"},
+	{Name: "Genshi", Type: Programming, Color: "#951531"},
+	{Name: "Gentoo Ebuild", Type: Programming, Color: "#9400ff"},
+	{Name: "Gentoo Eclass", Type: Programming, Color: "#9400ff"},
+	{Name: "Gherkin", Type: Programming, Color: "#5B2063"},
+	{Name: "GLSL", Type: Programming, Color: "#5686a5"},
+	{Name: "Glyph", Type: Programming, Color: "#c1ac7f"},
+	{Name: "Gnuplot", Type: Programming, Color: "#f0a9f0"},
+	{Name: "Go", Type: Programming, Color: "#00ADD8", Aliases: []string{"golang"}},
+	{Name: "Golo", Type: Programming, Color: "#88562A"},
+	{Name: "Gosu", Type: Programming, Color: "#82937f"},
+	{Name: "Grace", Type: Programming, Color: "#615f8b"},
+	{Name: "Gradle", Type: Data, Color: "#02303a"},
+	{Name: "Grammatical Framework", Type: Programming, Color: "#79aa7a"},
+	{Name: "Groovy", Type: Programming, Color: "#e69f56"},
+	{Name: "Groovy Server Pages", Type: Programming, Color: "#e69f56"},
+	{Name: "Hack", Type: Programming, Color: "#878787"},
+	{Name: "Haml", Type: Markup, Color: "#ece2a9"},
+	{Name: "Handlebars", Type: Markup, Color: "#f7931e"},
+	{Name: "Harbour", Type: Programming, Color: "#0e60e3"},
+	{Name: "Haskell", Type: Programming, Color: "#5e5086"},
+	{Name: "Haxe", Type: Programming, Color: "#df7900"},
+	{Name: "HCL", Type: Programming, Color: "#613361", Aliases: []string{"terraform"}},
+	{Name: "HLSL", Type: Programming, Color: "#aace60"},
+	{Name: "HTML", Type: Markup, Color: "#e34c26", Aliases: []string{"xhtml", "html/css"}},
+	{Name: "Hy", Type: Programming, Color: "#7790B2"},
+	{Name: "HyPhy", Type: Programming, Color: "#613361"},
+	{Name: "IDL", Type: Programming, Color: "#a3522f"},
+	{Name: "Idris", Type: Programming, Color: "#b30000"},
+	{Name: "IGOR Pro", Type: Programming, Color: "#0000cc"},
+	{Name: "Inform 7", Type: Programming, Color: "#613361"},
+	{Name: "INI", Type: Data, Color: "#d1dbe0"},
+	{Name: "Inno Setup", Type: Programming, Color: "#264b99"},
+	{Name: "Io", Type: Programming, Color: "#a9188d"},
+	{Name: "Ioke", Type: Programming, Color: "#078193"},
+	{Name: "Isabelle", Type: Programming, Color: "#FEFE00"},
+	{Name: "J", Type: Programming, Color: "#9EEDFF"},
+	{Name: "Jasmin", Type: Programming, Color: "#d03600"},
+	{Name: "Java", Type: Programming, Color: "#b07219"},
+	{Name: "JavaScript", Type: Programming, Color: "#f1e05a", Aliases: []string{"js", "node"}},
+	{Name: "JFlex", Type: Programming, Color: "#DBCA00"},
+	{Name: "JSON", Type: Data, Color: "#292929", Aliases: []string{"geojson", "jsonl", "topojson"}},
+	{Name: "JSONiq", Type: Programming, Color: "#40d47e"},
+	{Name: "Julia", Type: Programming, Color: "#a270ba"},
+	{Name: "Jupyter Notebook", Type: Markup, Color: "#DA5B0B"},
+	{Name: "Kotlin", Type: Programming, Color: "#A97BFF"},
+	{Name: "KRL", Type: Programming, Color: "#284307"},
+	{Name: "LabVIEW", Type: Programming, Color: "#fede06"},
+	{Name: "Lasso", Type: Programming, Color: "#999999"},
+	{Name: "Latte", Type: Markup, Color: "#f2a542"},
+	{Name: "Lean", Type: Programming, Color: "#613361"},
+	{Name: "Less", Type: Markup, Color: "#1d365d"},
+	{Name: "LFE", Type: Programming, Color: "#4C3023"},
+	{Name: "LiveScript", Type: Programming, Color: "#499886"},
+	{Name: "LLVM", Type: Programming, Color: "#185619"},
+	{Name: "Logos", Type: Programming, Color: "#613361"},
+	{Name: "Logtalk", Type: Programming, Color: "#295b9a"},
+	{Name: "Lolcode", Type: Programming, Color: "#848bf5"},
+	{Name: "LookML", Type: Programming, Color: "#652B81"},
+	{Name: "LoomScript", Type: Programming, Color: "#613361"},
+	{Name: "LSL", Type: Programming, Color: "#3d9970"},
+	{Name: "Lua", Type: Programming, Color: "#000080"},
+	{Name: "M", Type: Programming, Color: "#613361"},
+	{Name: "Makefile", Type: Programming, Color: "#427819", Aliases: []string{"bsdmake", "make", "mf"}},
+	{Name: "Mako", Type: Programming, Color: "#7e858d"},
+	{Name: "Markdown", Type: Prose, Color: "#083fa1", Aliases: []string{"md", "pandoc"}},
+	{Name: "Mask", Type: Markup, Color: "#f97732"},
+	{Name: "Mathematica", Type: Programming, Color: "#dd1100", Aliases: []string{"mma", "wolfram"}},
+	{Name: "Matlab", Type: Programming, Color: "#e16737", Aliases: []string{"octave"}},
+	{Name: "Max", Type: Programming, Color: "#c4a79c"},
+	{Name: "MAXScript", Type: Programming, Color: "#00a6a6"},
+	{Name: "Mercury", Type: Programming, Color: "#ff2b2b"},
+	{Name: "Metal", Type: Programming, Color: "#8f14e9"},
+	{Name: "Mirah", Type: Programming, Color: "#c7a938"},
+	{Name: "Modelica", Type: Programming, Color: "#de1d31"},
+	{Name: "Modula-2", Type: Programming, Color: "#10253f"},
+	{Name: "Module Management System", Type: Programming, Color: "#198CE7"},
+	{Name: "Monkey", Type: Programming, Color: "#613361"},
+	{Name: "Moocode", Type: Programming, Color: "#613361"},
+	{Name: "MoonScript", Type: Programming, Color: "#613361"},
+	{Name: "MTML", Type: Markup, Color: "#b7e1f4"},
+	{Name: "MUF", Type: Programming, Color: "#613361"},
+	{Name: "mupad", Type: Programming, Color: "#244061"},
+	{Name: "Myghty", Type: Programming, Color: "#613361"},
+	{Name: "NCL", Type: Programming, Color: "#28431f"},
+	{Name: "Nemerle", Type: Programming, Color: "#3d3c6e"},
+	{Name: "nesC", Type: Programming, Color: "#94B0C7"},
+	{Name: "NetLinx", Type: Programming, Color: "#0aa0ff"},
+	{Name: "NetLinx+ERB", Type: Programming, Color: "#747faa"},
+	{Name: "NetLogo", Type: Programming, Color: "#ff6375"},
+	{Name: "NewLisp", Type: Programming, Color: "#8767ef"},
+	{Name: "Nimrod", Type: Programming, Color: "#37775b", Aliases: []string{"nim"}},
+	{Name: "Nit", Type: Programming, Color: "#009917"},
+	{Name: "Nix", Type: Programming, Color: "#7e7eff"},
+	{Name: "Nu", Type: Programming, Color: "#c9df40"},
+	{Name: "Objective-C", Type: Programming, Color: "#438eff", Aliases: []string{"obj-c", "objc", "objectivec"}},
+	{Name: "Objective-C++", Type: Programming, Color: "#6866fb", Aliases: []string{"obj-c++", "objc++", "objectivec++"}},
+	{Name: "Objective-J", Type: Programming, Color: "#ff0c5a"},
+	{Name: "OCaml", Type: Programming, Color: "#3be133"},
+	{Name: "Omgrofl", Type: Programming, Color: "#cabbff"},
+	{Name: "ooc", Type: Programming, Color: "#b0b318"},
+	{Name: "Opal", Type: Programming, Color: "#f7ede0"},
+	{Name: "Oxygene", Type: Programming, Color: "#cdd0e3"},
+	{Name: "Oz", Type: Programming, Color: "#fab738"},
+	{Name: "Pan", Type: Programming, Color: "#cc0000"},
+	{Name: "Papyrus", Type: Programming, Color: "#6600cc"},
+	{Name: "Parrot", Type: Programming, Color: "#f3ca0a"},
+	{Name: "Pascal", Type: Programming, Color: "#E3F171"},
+	{Name: "PAWN", Type: Programming, Color: "#dbb284"},
+	{Name: "Perl", Type: Programming, Color: "#0298c3"},
+	{Name: "Perl6", Type: Programming, Color: "#0000fb", Aliases: []string{"raku", "perl-6"}},
+	{Name: "PHP", Type: Programming, Color: "#4F5D95"},
+	{Name: "PigLatin", Type: Programming, Color: "#fcd7de"},
+	{Name: "Pike", Type: Programming, Color: "#005390"},
+	{Name: "PLSQL", Type: Programming, Color: "#dad8d8", Aliases: []string{"pl/sql"}},
+	{Name: "PogoScript", Type: Programming, Color: "#d80074"},
+	{Name: "PowerShell", Type: Programming, Color: "#012456", Aliases: []string{"posh", "pwsh"}},
+	{Name: "Processing", Type: Programming, Color: "#0096D8"},
+	{Name: "Prolog", Type: Programming, Color: "#74283c"},
+	{Name: "Propeller Spin", Type: Programming, Color: "#7fa2a7"},
+	{Name: "Puppet", Type: Programming, Color: "#302B6D"},
+	{Name: "Pure Data", Type: Programming, Color: "#91de79"},
+	{Name: "PureBasic", Type: Programming, Color: "#5a6986"},
+	{Name: "PureScript", Type: Programming, Color: "#1D222D"},
+	{Name: "Python", Type: Programming, Color: "#3572A5", Aliases: []string{"python3", "py"}},
+	{Name: "QMake", Type: Programming, Color: "#613361"},
+	{Name: "QML", Type: Programming, Color: "#44a51c"},
+	{Name: "R", Type: Programming, Color: "#198CE7"},
+	{Name: "Racket", Type: Programming, Color: "#22228f"},
+	{Name: "Ragel", Type: Programming, Color: "#9d5200"},
+	{Name: "RAML", Type: Markup, Color: "#77d9fb"},
+	{Name: "Rebol", Type: Programming, Color: "#358a5b"},
+	{Name: "Red", Type: Programming, Color: "#f50000"},
+	{Name: "Ren'Py", Type: Programming, Color: "#ff7f7f"},
+	{Name: "reStructuredText", Type: Prose, Color: "#141414", Aliases: []string{"rst"}},
+	{Name: "Rouge", Type: Programming, Color: "#cc0088"},
+	{Name: "Ruby", Type: Programming, Color: "#701516", Aliases: []string{"jruby", "macruby", "rake", "rb", "rbx"}},
+	{Name: "Rust", Type: Programming, Color: "#dea584", Aliases: []string{"rs"}},
+	{Name: "SaltStack", Type: Programming, Color: "#646464"},
+	{Name: "SAS", Type: Programming, Color: "#B34936"},
+	{Name: "Scala", Type: Programming, Color: "#c22d40"},
+	{Name: "Scheme", Type: Programming, Color: "#1e4aec"},
+	{Name: "Scilab", Type: Programming, Color: "#ca0f21"},
+	{Name: "SCSS", Type: Markup, Color: "#c6538c"},
+	{Name: "Self", Type: Programming, Color: "#0579aa"},
+	{Name: "Shell", Type: Programming, Color: "#89e051", Aliases: []string{"sh", "shell-script", "bash", "zsh"}},
+	{Name: "Shen", Type: Programming, Color: "#120F14"},
+	{Name: "Slash", Type: Programming, Color: "#007edc"},
+	{Name: "Slim", Type: Markup, Color: "#2b2b2b"},
+	{Name: "Smalltalk", Type: Programming, Color: "#596706"},
+	{Name: "SourcePawn", Type: Programming, Color: "#5c7611"},
+	{Name: "SQF", Type: Programming, Color: "#3F3F3F"},
+	{Name: "SQL", Type: Programming, Color: "#e38c00"},
+	{Name: "Squirrel", Type: Programming, Color: "#800000"},
+	{Name: "Standard ML", Type: Programming, Color: "#dc566d", Aliases: []string{"sml"}},
+	{Name: "Stata", Type: Programming, Color: "#1a5f91"},
+	{Name: "Stylus", Type: Markup, Color: "#ff6347"},
+	{Name: "SuperCollider", Type: Programming, Color: "#463029"},
+	{Name: "Svelte", Type: Markup, Color: "#ff3e00"},
+	{Name: "Swift", Type: Programming, Color: "#ffac45"},
+	{Name: "SystemVerilog", Type: Programming, Color: "#DAE1C2"},
+	{Name: "Tcl", Type: Programming, Color: "#e4cc98"},
+	{Name: "TeX", Type: Markup, Color: "#3D6117", Aliases: []string{"latex"}},
+	{Name: "Text", Type: Prose, Aliases: []string{"fundamental", "plain text"}},
+	{Name: "TOML", Type: Data, Color: "#9c4221"},
+	{Name: "Turing", Type: Programming, Color: "#cf142b"},
+	{Name: "TXL", Type: Programming, Color: "#0178b8"},
+	{Name: "TypeScript", Type: Programming, Color: "#3178c6", Aliases: []string{"ts"}},
+	{Name: "Unified Parallel C", Type: Programming, Color: "#4e3617"},
+	{Name: "Unity3D Asset", Type: Data, Color: "#ab69a1"},
+	{Name: "UnrealScript", Type: Programming, Color: "#a54c4d"},
+	{Name: "Vala", Type: Programming, Color: "#fbe5cd"},
+	{Name: "Verilog", Type: Programming, Color: "#b2b7f8"},
+	{Name: "VHDL", Type: Programming, Color: "#adb2cb"},
+	{Name: "VimL", Type: Programming, Color: "#199f4b", Aliases: []string{"vim", "vimscript", "vim script"}},
+	{Name: "Visual Basic", Type: Programming, Color: "#945db7", Aliases: []string{"vb.net", "vbnet", "visual basic .net"}},
+	{Name: "Volt", Type: Programming, Color: "#1F1F1F"},
+	{Name: "Vue", Type: Markup, Color: "#41b883"},
+	{Name: "Web Ontology Language", Type: Data, Color: "#9cc9dd"},
+	{Name: "wisp", Type: Programming, Color: "#7587a6"},
+	{Name: "X10", Type: Programming, Color: "#4B6BEF"},
+	{Name: "xBase", Type: Programming, Color: "#403a40"},
+	{Name: "XC", Type: Programming, Color: "#99DA07"},
+	{Name: "XML", Type: Data, Color: "#0060ac"},
+	{Name: "XProc", Type: Programming, Color: "#797979"},
+	{Name: "XQuery", Type: Programming, Color: "#5232e7"},
+	{Name: "XS", Type: Programming},
+	{Name: "XSLT", Type: Programming, Color: "#EB8CEB"},
+	{Name: "Xtend", Type: Programming, Color: "#24255d"},
+	{Name: "Yacc", Type: Programming, Color: "#4B6C4B"},
+	{Name: "YAML", Type: Data, Color: "#cb171e", Aliases: []string{"yml"}},
+	{Name: "Zephir", Type: Programming, Color: "#118f9e"},
+	{Name: "Zig", Type: Programming, Color: "#ec915c"},
+	{Name: "Zimpl", Type: Programming, Color: "#d67711"},
+}
@@ -0,0 +1,33 @@
+package all
+
+import (
+	"github.com/vukan322/devmetrics/internal/providers"
+	"github.com/vukan322/devmetrics/internal/providers/azuredevops"
+	"github.com/vukan322/devmetrics/internal/providers/bitbucket"
+	"github.com/vukan322/devmetrics/internal/providers/demo"
+	"github.com/vukan322/devmetrics/internal/providers/gerrit"
+	"github.com/vukan322/devmetrics/internal/providers/gitea"
+	"github.com/vukan322/devmetrics/internal/providers/github"
+	"github.com/vukan322/devmetrics/internal/providers/gitlab"
+	"github.com/vukan322/devmetrics/internal/providers/local"
+	"github.com/vukan322/devmetrics/internal/providers/packages"
+	"github.com/vukan322/devmetrics/internal/providers/sourcehut"
+)
+
+func init() {
+	providers.Register(github.Factory())
+	providers.Register(github.EnterpriseFactory())
+	providers.Register(bitbucket.Factory())
+	providers.Register(bitbucket.ServerFactory())
+	providers.Register(gitlab.Factory())
+	providers.Register(gitea.Factory())
+	providers.Register(local.Factory())
+	providers.Register(azuredevops.Factory())
+	providers.Register(sourcehut.Factory())
+	providers.Register(gerrit.Factory())
+	providers.Register(packages.NPMFactory())
+	providers.Register(packages.PyPIFactory())
+	providers.Register(packages.CratesFactory())
+	providers.Register(packages.GoProxyFactory())
+	providers.Register(demo.Factory())
+}
@@ -0,0 +1,445 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vukan322/devmetrics/internal/core"
+	"github.com/vukan322/devmetrics/internal/httpclient"
+)
+
+const (
+	defaultBaseURL = "https://dev.azure.com"
+	apiVersion     = "6.0"
+	pageSize       = 100
+)
+
+type Provider struct {
+	client        *http.Client
+	baseURL       string
+	token         string
+	organizations []string
+	projects      map[string]bool
+	filter        core.RepoFilter
+}
+
+func New(baseURL, token string, organizations, projects []string) *Provider {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	projectSet := make(map[string]bool, len(projects))
+	for _, pr := range projects {
+		projectSet[strings.ToLower(pr)] = true
+	}
+
+	return &Provider{
+		client:        httpclient.New(),
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		token:         token,
+		organizations: organizations,
+		projects:      projectSet,
+	}
+}
+
+func (p *Provider) Name() string {
+	return "azuredevops"
+}
+
+func (p *Provider) SetRepoFilter(f core.RepoFilter) {
+	p.filter = f
+}
+
+type connectionData struct {
+	AuthenticatedUser struct {
+		ID                  string `json:"id"`
+		ProviderDisplayName string `json:"providerDisplayName"`
+	} `json:"authenticatedUser"`
+}
+
+type azureProject struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Visibility string `json:"visibility"`
+}
+
+type azureRepo struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	IsDisabled bool   `json:"isDisabled"`
+	IsFork     bool   `json:"isFork"`
+	WebURL     string `json:"webUrl"`
+}
+
+type azurePullRequest struct {
+	Status string `json:"status"`
+}
+
+type azureCommit struct {
+	CommitID string `json:"commitId"`
+	Author   struct {
+		Email string    `json:"email"`
+		Date  time.Time `json:"date"`
+	} `json:"author"`
+}
+
+type listResponse[T any] struct {
+	Count int `json:"count"`
+	Value []T `json:"value"`
+}
+
+func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
+	if len(p.organizations) == 0 {
+		return core.DevStats{}, fmt.Errorf("azuredevops: no organizations configured")
+	}
+
+	var (
+		displayName   string
+		publicRepos   int
+		privateRepos  int
+		filteredRepos int
+		totalCommits  int
+		prStats       core.PRStats
+		repositories  []core.Repository
+	)
+	contribs := make(map[time.Time]int)
+	since := time.Now().UTC().AddDate(-1, 0, 0)
+
+	var (
+		repoCount      int
+		projectCount   int
+		repoFailures   int
+		commitFailures int
+		prFailures     int
+	)
+
+	for _, org := range p.organizations {
+		conn, err := p.fetchConnectionData(ctx, org)
+		if err != nil {
+			return core.DevStats{}, fmt.Errorf("azuredevops: fetch connection data for %s: %w", org, err)
+		}
+		if displayName == "" {
+			displayName = conn.AuthenticatedUser.ProviderDisplayName
+		}
+
+		projects, err := p.fetchProjects(ctx, org)
+		if err != nil {
+			return core.DevStats{}, fmt.Errorf("azuredevops: fetch projects for %s: %w", org, err)
+		}
+
+		for _, project := range projects {
+			if len(p.projects) > 0 && !p.projects[strings.ToLower(project.Name)] {
+				continue
+			}
+
+			projectCount++
+
+			repos, err := p.fetchRepos(ctx, org, project.ID)
+			if err != nil {
+				log.Printf("azuredevops: fetchRepos error for %s/%s: %v", org, project.Name, err)
+				repoFailures++
+				continue
+			}
+
+			for _, repo := range repos {
+				if repo.IsDisabled {
+					continue
+				}
+				if !p.filter.Keep(core.RepoMeta{Name: repo.Name, FullName: project.Name + "/" + repo.Name, Fork: repo.IsFork}) {
+					filteredRepos++
+					continue
+				}
+				if project.Visibility == "public" {
+					publicRepos++
+				} else {
+					privateRepos++
+				}
+				repoCount++
+				repositories = append(repositories, core.Repository{
+					Provider: "azure",
+					Name:     org + "/" + project.Name + "/" + repo.Name,
+					URL:      repo.WebURL,
+					Private:  project.Visibility != "public",
+				})
+
+				days, err := p.fetchCommitsPerDay(ctx, org, project.ID, repo.ID, handle, since)
+				if err != nil {
+					log.Printf("azuredevops: fetchCommitsPerDay error for %s/%s/%s: %v", org, project.Name, repo.Name, err)
+					commitFailures++
+					continue
+				}
+				for day, count := range days {
+					contribs[day] += count
+					totalCommits += count
+				}
+			}
+
+			projectPRs, err := p.fetchPRStats(ctx, org, project.ID, conn.AuthenticatedUser.ID)
+			if err != nil {
+				log.Printf("azuredevops: fetchPRStats error for %s/%s: %v", org, project.Name, err)
+				prFailures++
+				continue
+			}
+			prStats.Open += projectPRs.Open
+			prStats.Merged += projectPRs.Merged
+			prStats.Closed += projectPRs.Closed
+		}
+	}
+
+	var diag core.Diagnostics
+	diag.RecordPartial("azure", core.FieldRepos, repoFailures, projectCount)
+	diag.RecordPartial("azure", core.FieldContributions, commitFailures+repoFailures, repoCount+repoFailures)
+	diag.RecordPartial("azure", core.FieldPullRequests, prFailures, projectCount)
+	diag.Unsupported("azure", core.FieldStars, core.FieldFollowers, core.FieldContributedRepos, core.FieldJoined, core.FieldLanguages, core.FieldIssues, core.FieldReviews, core.FieldPackages)
+
+	currentStreak, longestStreak := core.ComputeStreaks(contribs)
+
+	stats := core.DevStats{
+		Identity: core.Identity{
+			Name:     displayName,
+			Username: handle,
+			Handles:  []string{"azure: " + handle},
+		},
+		Totals: core.Totals{
+			PublicRepos:     publicRepos,
+			PrivateRepos:    privateRepos,
+			Commits:         totalCommits,
+			CurrentStreak:   currentStreak,
+			LongestStreak:   longestStreak,
+			CommitsThisWeek: core.CommitsThisWeek(contribs),
+			FilteredRepos:   filteredRepos,
+		},
+		Activity: core.Activity{
+			ContributionsPerDay: contribs,
+			PullRequests:        prStats,
+		},
+		Repositories: repositories,
+		Diagnostics:  diag,
+	}
+
+	return stats, nil
+}
+
+func (p *Provider) fetchConnectionData(ctx context.Context, org string) (*connectionData, error) {
+	endpoint := fmt.Sprintf("%s/%s/_apis/connectionData", p.baseURL, url.PathEscape(org))
+
+	resp, err := p.get(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var c connectionData
+	if err := json.NewDecoder(resp.Body).Decode(&c); err != nil {
+		return nil, fmt.Errorf("decode connection data response: %w", err)
+	}
+	if c.AuthenticatedUser.ID == "" {
+		return nil, fmt.Errorf("no authenticated user, check DEV_METRICS_AZURE_DEVOPS_TOKEN")
+	}
+
+	return &c, nil
+}
+
+func (p *Provider) fetchProjects(ctx context.Context, org string) ([]azureProject, error) {
+	var all []azureProject
+	continuation := ""
+
+	for {
+		query := url.Values{}
+		query.Set("api-version", apiVersion)
+		query.Set("$top", fmt.Sprint(pageSize))
+		if continuation != "" {
+			query.Set("continuationToken", continuation)
+		}
+
+		endpoint := fmt.Sprintf("%s/%s/_apis/projects?%s", p.baseURL, url.PathEscape(org), query.Encode())
+
+		resp, err := p.get(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		var page listResponse[azureProject]
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("decode projects response: %w", err)
+		}
+		resp.Body.Close()
+
+		all = append(all, page.Value...)
+
+		continuation = resp.Header.Get("X-Ms-Continuationtoken")
+		if continuation == "" {
+			break
+		}
+	}
+
+	return all, nil
+}
+
+func (p *Provider) fetchRepos(ctx context.Context, org, projectID string) ([]azureRepo, error) {
+	endpoint := fmt.Sprintf(
+		"%s/%s/%s/_apis/git/repositories?api-version=%s",
+		p.baseURL,
+		url.PathEscape(org),
+		url.PathEscape(projectID),
+		apiVersion,
+	)
+
+	resp, err := p.get(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var page listResponse[azureRepo]
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("decode repositories response: %w", err)
+	}
+
+	return page.Value, nil
+}
+
+func (p *Provider) fetchPRStats(ctx context.Context, org, projectID, creatorID string) (core.PRStats, error) {
+	var stats core.PRStats
+
+	for skip := 0; ; skip += pageSize {
+		query := url.Values{}
+		query.Set("api-version", apiVersion)
+		query.Set("searchCriteria.creatorId", creatorID)
+		query.Set("searchCriteria.status", "all")
+		query.Set("$top", fmt.Sprint(pageSize))
+		query.Set("$skip", fmt.Sprint(skip))
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/%s/_apis/git/pullrequests?%s",
+			p.baseURL,
+			url.PathEscape(org),
+			url.PathEscape(projectID),
+			query.Encode(),
+		)
+
+		resp, err := p.get(ctx, endpoint)
+		if err != nil {
+			return core.PRStats{}, err
+		}
+
+		var page listResponse[azurePullRequest]
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+			resp.Body.Close()
+			return core.PRStats{}, fmt.Errorf("decode pull requests response: %w", err)
+		}
+		resp.Body.Close()
+
+		for _, pr := range page.Value {
+			switch pr.Status {
+			case "active":
+				stats.Open++
+			case "completed":
+				stats.Merged++
+			case "abandoned":
+				stats.Closed++
+			}
+		}
+
+		if len(page.Value) < pageSize {
+			break
+		}
+	}
+
+	return stats, nil
+}
+
+func (p *Provider) fetchCommitsPerDay(ctx context.Context, org, projectID, repoID, author string, since time.Time) (map[time.Time]int, error) {
+	m := make(map[time.Time]int)
+	seen := make(map[string]bool)
+
+	for skip := 0; ; skip += pageSize {
+		query := url.Values{}
+		query.Set("api-version", apiVersion)
+		query.Set("searchCriteria.author", author)
+		query.Set("searchCriteria.fromDate", since.Format(time.RFC3339))
+		query.Set("searchCriteria.$top", fmt.Sprint(pageSize))
+		query.Set("searchCriteria.$skip", fmt.Sprint(skip))
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/%s/_apis/git/repositories/%s/commits?%s",
+			p.baseURL,
+			url.PathEscape(org),
+			url.PathEscape(projectID),
+			url.PathEscape(repoID),
+			query.Encode(),
+		)
+
+		resp, err := p.get(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		var page listResponse[azureCommit]
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("decode commits response: %w", err)
+		}
+		resp.Body.Close()
+
+		added := 0
+		for _, c := range page.Value {
+			if seen[c.CommitID] {
+				continue
+			}
+			seen[c.CommitID] = true
+			added++
+
+			t := c.Author.Date.UTC()
+			m[time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)]++
+		}
+
+		if added == 0 || len(page.Value) < pageSize {
+			break
+		}
+	}
+
+	return m, nil
+}
+
+func (p *Provider) get(ctx context.Context, endpoint string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	p.applyAuth(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusNonAuthoritativeInfo {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unauthorized (%d), check DEV_METRICS_AZURE_DEVOPS_TOKEN", resp.StatusCode)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	return resp, nil
+}
+
+func (p *Provider) applyAuth(req *http.Request) {
+	req.Header.Set("Accept", "application/json")
+	if p.token == "" {
+		return
+	}
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(":" + p.token))
+	req.Header.Set("Authorization", "Basic "+encoded)
+}
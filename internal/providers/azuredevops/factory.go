@@ -0,0 +1,24 @@
+package azuredevops
+
+import (
+	"github.com/vukan322/devmetrics/internal/providers"
+)
+
+func Factory() providers.Factory {
+	return providers.Factory{
+		Name:     "azuredevops",
+		Title:    "Azure DevOps",
+		Prefix:   "DEV_METRICS_AZURE_DEVOPS",
+		Multiple: true,
+		Keys: []providers.Key{
+			{Name: "TOKEN", Required: true, Usage: "personal access token (Code: Read)"},
+			{Name: "USER", Required: true, Usage: "commit author email or name"},
+			{Name: "ORGS", Required: true, Usage: "comma-separated organizations or collections"},
+			{Name: "PROJECTS", Usage: "comma-separated project names to limit the scan"},
+			{Name: "URL", Default: defaultBaseURL, Usage: "service URL, or the collection root for Azure DevOps Server"},
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			return New(cfg.Get("URL"), cfg.Get("TOKEN"), cfg.List("ORGS"), cfg.List("PROJECTS")), cfg.Get("USER"), nil
+		},
+	}
+}
@@ -5,28 +5,32 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"net/url"
 	"time"
 
 	"github.com/vukan322/devmetrics/internal/core"
+	"github.com/vukan322/devmetrics/internal/httpclient"
+	"github.com/vukan322/devmetrics/internal/languages"
 )
 
 type Provider struct {
-	client  *http.Client
-	baseURL string
-	email   string
-	token   string
-	user    string
+	client     *http.Client
+	baseURL    string
+	email      string
+	token      string
+	workspaces []string
+	filter     core.RepoFilter
 }
 
-func New(email, token, user string) *Provider {
+func New(email, token string, workspaces ...string) *Provider {
 	return &Provider{
-		client:  &http.Client{Timeout: 10 * time.Second},
-		baseURL: "https://api.bitbucket.org/2.0",
-		email:   email,
-		token:   token,
-		user:    user,
+		client:     httpclient.New(),
+		baseURL:    "https://api.bitbucket.org/2.0",
+		email:      email,
+		token:      token,
+		workspaces: workspaces,
 	}
 }
 
@@ -34,6 +38,10 @@ func (p *Provider) Name() string {
 	return "bitbucket"
 }
 
+func (p *Provider) SetRepoFilter(f core.RepoFilter) {
+	p.filter = f
+}
+
 type bitbucketUser struct {
 	AccountID   string `json:"account_id"`
 	Username    string `json:"username"`
@@ -47,8 +55,19 @@ type bitbucketUser struct {
 }
 
 type bitbucketRepo struct {
+	Slug      string `json:"slug"`
+	FullName  string `json:"full_name"`
 	IsPrivate bool   `json:"is_private"`
 	Language  string `json:"language"`
+	Size      int64  `json:"size"`
+	Parent    *struct {
+		FullName string `json:"full_name"`
+	} `json:"parent"`
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
 }
 
 type pagedReposResponse struct {
@@ -56,16 +75,44 @@ type pagedReposResponse struct {
 	Next   string          `json:"next"`
 }
 
+type bitbucketPullRequest struct {
+	State string `json:"state"`
+}
+
+type pagedPullRequestsResponse struct {
+	Values []bitbucketPullRequest `json:"values"`
+	Next   string                 `json:"next"`
+}
+
+type bitbucketCommit struct {
+	Date   time.Time `json:"date"`
+	Author struct {
+		User struct {
+			AccountID string `json:"account_id"`
+		} `json:"user"`
+	} `json:"author"`
+}
+
+type pagedCommitsResponse struct {
+	Values []bitbucketCommit `json:"values"`
+	Next   string            `json:"next"`
+}
+
 func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
 	user, err := p.fetchUser(ctx)
 	if err != nil {
 		return core.DevStats{}, fmt.Errorf("bitbucket: fetch user: %w", err)
 	}
 
-	repos, err := p.fetchRepos(ctx, p.user)
-	if err != nil {
-		return core.DevStats{}, fmt.Errorf("bitbucket: fetch repos: %w", err)
+	var repos []bitbucketRepo
+	for _, workspace := range p.workspaces {
+		wsRepos, err := p.fetchRepos(ctx, workspace)
+		if err != nil {
+			return core.DevStats{}, fmt.Errorf("bitbucket: fetch repos for workspace %s: %w", workspace, err)
+		}
+		repos = append(repos, wsRepos...)
 	}
+	repos, skipped := p.filterRepos(repos)
 
 	publicRepos := 0
 	privateRepos := 0
@@ -77,22 +124,79 @@ func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, err
 		}
 	}
 
+	langs := computeLanguages(repos)
+
+	var diag core.Diagnostics
+	diag.Supplied("bitbucket", core.FieldRepos, core.FieldLanguages)
+	diag.Unsupported("bitbucket", core.FieldStars, core.FieldFollowers, core.FieldContributedRepos, core.FieldJoined, core.FieldIssues, core.FieldReviews, core.FieldPackages)
+
+	prStats := core.PRStats{}
+	contribs := make(map[time.Time]int)
+	totalCommits := 0
+	since := time.Now().UTC().AddDate(-1, 0, 0)
+	prFailures := 0
+	commitFailures := 0
+
+	for _, r := range repos {
+		repoPRs, err := p.fetchPRStats(ctx, r.FullName, user.AccountID)
+		if err != nil {
+			log.Printf("bitbucket: fetchPRStats error for %s: %v", r.FullName, err)
+			prFailures++
+		} else {
+			prStats.Open += repoPRs.Open
+			prStats.Merged += repoPRs.Merged
+			prStats.Closed += repoPRs.Closed
+		}
+
+		days, err := p.fetchCommitsPerDay(ctx, r.FullName, user.AccountID, since)
+		if err != nil {
+			log.Printf("bitbucket: fetchCommitsPerDay error for %s: %v", r.FullName, err)
+			commitFailures++
+			continue
+		}
+		for day, count := range days {
+			contribs[day] += count
+			totalCommits += count
+		}
+	}
+
+	diag.RecordPartial("bitbucket", core.FieldPullRequests, prFailures, len(repos))
+	diag.RecordPartial("bitbucket", core.FieldContributions, commitFailures, len(repos))
+
+	currentStreak, longestStreak := core.ComputeStreaks(contribs)
+
+	avatarData, err := httpclient.FetchAvatar(ctx, p.client, user.Links.Avatar.Href)
+	if err != nil {
+		avatarData = ""
+	}
+
 	identity := core.Identity{
-		Name:     user.DisplayName,
+		Name:     core.DisplayName(user.DisplayName, user.Nickname),
 		Username: user.Nickname,
-		Avatar:   "",
+		Avatar:   avatarData,
 		Handles:  []string{"bitbucket: " + handle},
 	}
 
 	totals := core.Totals{
-		PublicRepos:  publicRepos,
-		PrivateRepos: privateRepos,
+		PublicRepos:     publicRepos,
+		PrivateRepos:    privateRepos,
+		Commits:         totalCommits,
+		CurrentStreak:   currentStreak,
+		LongestStreak:   longestStreak,
+		CommitsThisWeek: core.CommitsThisWeek(contribs),
+		FilteredRepos:   len(skipped),
 	}
 
 	stats := core.DevStats{
 		Identity: identity,
 		Totals:   totals,
-		Activity: core.Activity{},
+		Activity: core.Activity{
+			ContributionsPerDay: contribs,
+			TopLanguages:        langs,
+			PullRequests:        prStats,
+		},
+		Repositories: repositories(repos),
+		Diagnostics:  diag,
 	}
 
 	return stats, nil
@@ -162,6 +266,168 @@ func (p *Provider) fetchRepos(ctx context.Context, handle string) ([]bitbucketRe
 	return all, nil
 }
 
+func (p *Provider) fetchPRStats(ctx context.Context, fullName, accountID string) (core.PRStats, error) {
+	var stats core.PRStats
+
+	query := url.Values{}
+	query.Set("q", fmt.Sprintf("author.account_id=%q", accountID))
+	query.Set("pagelen", "50")
+	query.Set("fields", "next,values.state")
+	for _, state := range []string{"OPEN", "MERGED", "DECLINED", "SUPERSEDED"} {
+		query.Add("state", state)
+	}
+
+	nextURL := fmt.Sprintf("%s/repositories/%s/pullrequests?%s", p.baseURL, fullName, query.Encode())
+
+	for nextURL != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, nextURL, nil)
+		if err != nil {
+			return core.PRStats{}, fmt.Errorf("new pull requests request: %w", err)
+		}
+		p.applyAuth(req)
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return core.PRStats{}, fmt.Errorf("do pull requests request: %w", err)
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			resp.Body.Close()
+			return core.PRStats{}, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, nextURL)
+		}
+
+		var page pagedPullRequestsResponse
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+			resp.Body.Close()
+			return core.PRStats{}, fmt.Errorf("decode pull requests response: %w", err)
+		}
+		resp.Body.Close()
+
+		for _, pr := range page.Values {
+			switch pr.State {
+			case "OPEN":
+				stats.Open++
+			case "MERGED":
+				stats.Merged++
+			case "DECLINED", "SUPERSEDED":
+				stats.Closed++
+			}
+		}
+
+		nextURL = page.Next
+	}
+
+	return stats, nil
+}
+
+func (p *Provider) fetchCommitsPerDay(ctx context.Context, fullName, accountID string, since time.Time) (map[time.Time]int, error) {
+	m := make(map[time.Time]int)
+	nextURL := fmt.Sprintf(
+		"%s/repositories/%s/commits?pagelen=100&fields=next,values.date,values.author.user.account_id",
+		p.baseURL,
+		fullName,
+	)
+
+	for nextURL != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, nextURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("new commits request: %w", err)
+		}
+		p.applyAuth(req)
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("do commits request: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			return m, nil
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, nextURL)
+		}
+
+		var page pagedCommitsResponse
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("decode commits response: %w", err)
+		}
+		resp.Body.Close()
+
+		recent := core.CountCommitPage(m, page.Values, since, func(c bitbucketCommit) (time.Time, bool) {
+			return c.Date, c.Author.User.AccountID == accountID
+		})
+		if !recent {
+			break
+		}
+		nextURL = page.Next
+	}
+
+	return m, nil
+}
+
+func (p *Provider) filterRepos(repos []bitbucketRepo) (kept, skipped []bitbucketRepo) {
+	for _, r := range repos {
+		meta := core.RepoMeta{
+			Name:     r.Slug,
+			FullName: r.FullName,
+			Fork:     r.Parent != nil,
+		}
+		if p.filter.Keep(meta) {
+			kept = append(kept, r)
+		} else {
+			skipped = append(skipped, r)
+		}
+	}
+	return kept, skipped
+}
+
+func computeLanguages(repos []bitbucketRepo) []core.LanguageStat {
+	counts := make(map[string]int64)
+	for _, r := range repos {
+		if r.Language == "" || r.Size <= 0 {
+			continue
+		}
+		counts[languages.Normalize(r.Language)] += r.Size
+	}
+
+	langs := make([]core.LanguageStat, 0, len(counts))
+	for name, c := range counts {
+		langs = append(langs, core.LanguageStat{
+			Name:  name,
+			Bytes: c,
+			Color: languages.Color(name),
+		})
+	}
+	return langs
+}
+
+func repositories(repos []bitbucketRepo) []core.Repository {
+	out := make([]core.Repository, 0, len(repos))
+	for _, r := range repos {
+		repo := core.Repository{
+			Provider: "bitbucket",
+			Name:     r.FullName,
+			URL:      r.Links.HTML.Href,
+			Private:  r.IsPrivate,
+		}
+		if repo.URL == "" {
+			repo.URL = "https://bitbucket.org/" + r.FullName
+		}
+		if r.Parent != nil {
+			repo.Source = "https://bitbucket.org/" + r.Parent.FullName
+		}
+		if r.Language != "" && r.Size > 0 {
+			name := languages.Normalize(r.Language)
+			repo.Languages = []core.LanguageStat{{Name: name, Bytes: r.Size, Color: languages.Color(name)}}
+		}
+		out = append(out, repo)
+	}
+	return out
+}
+
 func (p *Provider) applyAuth(req *http.Request) {
 	if p.email == "" || p.token == "" {
 		return
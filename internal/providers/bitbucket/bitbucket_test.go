@@ -0,0 +1,114 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testCommit struct {
+	day  int
+	mine bool
+}
+
+func day(n int) time.Time {
+	return time.Date(2026, 3, n, 0, 0, 0, 0, time.UTC)
+}
+
+func TestFetchCommitsPerDayPaging(t *testing.T) {
+	since := day(10)
+
+	// The second commit on the first page is an old merge listed before newer
+	// work, and the fourth page must never be requested.
+	pages := [][]testCommit{
+		{{20, true}, {2, true}, {18, true}},
+		{{15, false}, {12, true}, {1, true}},
+		{{5, true}, {4, true}},
+		{{14, true}},
+	}
+	want := map[time.Time]int{day(20): 1, day(18): 1, day(12): 1}
+
+	tests := []struct {
+		name  string
+		page  func(srvURL string, index int, commits []testCommit) any
+		fetch func(ctx context.Context, srvURL string) (map[time.Time]int, error)
+	}{
+		{
+			name: "cloud",
+			page: func(srvURL string, index int, commits []testCommit) any {
+				var page pagedCommitsResponse
+				for _, c := range commits {
+					var bc bitbucketCommit
+					bc.Date = day(c.day).Add(9 * time.Hour)
+					bc.Author.User.AccountID = "other"
+					if c.mine {
+						bc.Author.User.AccountID = "me"
+					}
+					page.Values = append(page.Values, bc)
+				}
+				if index+1 < len(pages) {
+					page.Next = srvURL + "/next"
+				}
+				return page
+			},
+			fetch: func(ctx context.Context, srvURL string) (map[time.Time]int, error) {
+				p := New("", "")
+				p.baseURL = srvURL
+				return p.fetchCommitsPerDay(ctx, "ada/engine", "me", since)
+			},
+		},
+		{
+			name: "server",
+			page: func(srvURL string, index int, commits []testCommit) any {
+				page := serverPage[serverCommit]{
+					IsLastPage:    index+1 == len(pages),
+					NextPageStart: (index + 1) * serverPageLimit,
+				}
+				for _, c := range commits {
+					var sc serverCommit
+					sc.AuthorTimestamp = day(c.day).Add(9 * time.Hour).UnixMilli()
+					sc.Author.Name = "bob"
+					if c.mine {
+						sc.Author.Name = "Ada"
+					}
+					page.Values = append(page.Values, sc)
+				}
+				return page
+			},
+			fetch: func(ctx context.Context, srvURL string) (map[time.Time]int, error) {
+				repo := serverRepo{Slug: "engine"}
+				repo.Project.Key = "ADA"
+				user := &serverUser{Name: "ada", Slug: "ada"}
+				return NewServer(srvURL, "", nil).fetchCommitsPerDay(ctx, repo, user, since)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested := 0
+			var srv *httptest.Server
+			srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				index := requested
+				requested++
+				json.NewEncoder(w).Encode(tt.page(srv.URL, index, pages[index]))
+			}))
+			defer srv.Close()
+
+			got, err := tt.fetch(context.Background(), srv.URL)
+			if err != nil {
+				t.Fatalf("fetchCommitsPerDay: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("commits per day = %v, want %v", got, want)
+			}
+			if requested != 3 {
+				t.Errorf("requested %d pages, want 3", requested)
+			}
+		})
+	}
+}
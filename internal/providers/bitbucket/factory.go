@@ -0,0 +1,48 @@
+package bitbucket
+
+import (
+	"github.com/vukan322/devmetrics/internal/providers"
+)
+
+func Factory() providers.Factory {
+	return providers.Factory{
+		Name:     "bitbucket",
+		Title:    "Bitbucket",
+		Prefix:   "DEV_METRICS_BITBUCKET",
+		Multiple: true,
+		Keys: []providers.Key{
+			{Name: "EMAIL", Required: true, Usage: "Atlassian account email"},
+			{Name: "TOKEN", Required: true, Usage: "Atlassian API token"},
+			{Name: "WORKSPACE", Required: true, Usage: "comma-separated workspaces to aggregate"},
+			{Name: "USER", Usage: "display handle (defaults to the first workspace)"},
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			workspaces := cfg.List("WORKSPACE")
+
+			handle := cfg.Get("USER")
+			if handle == "" && len(workspaces) > 0 {
+				handle = workspaces[0]
+			}
+
+			return New(cfg.Get("EMAIL"), cfg.Get("TOKEN"), workspaces...), handle, nil
+		},
+	}
+}
+
+func ServerFactory() providers.Factory {
+	return providers.Factory{
+		Name:     "bitbucket-server",
+		Title:    "Bitbucket Data Center",
+		Prefix:   "DEV_METRICS_BITBUCKET_SERVER",
+		Multiple: true,
+		Keys: []providers.Key{
+			{Name: "URL", Required: true, Usage: "Bitbucket Server/Data Center base URL"},
+			{Name: "TOKEN", Required: true, Usage: "HTTP access token"},
+			{Name: "USER", Required: true, Usage: "user slug"},
+			{Name: "PROJECTS", Usage: "comma-separated project keys (defaults to the personal project)"},
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			return NewServer(cfg.Get("URL"), cfg.Get("TOKEN"), cfg.List("PROJECTS")), cfg.Get("USER"), nil
+		},
+	}
+}
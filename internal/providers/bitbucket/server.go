@@ -0,0 +1,374 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vukan322/devmetrics/internal/core"
+	"github.com/vukan322/devmetrics/internal/httpclient"
+)
+
+const serverPageLimit = 100
+
+type ServerProvider struct {
+	client   *http.Client
+	baseURL  string
+	token    string
+	projects []string
+	label    string
+	filter   core.RepoFilter
+}
+
+func NewServer(baseURL, token string, projects []string) *ServerProvider {
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	return &ServerProvider{
+		client:   httpclient.New(),
+		baseURL:  strings.TrimSuffix(baseURL, "/rest/api/1.0") + "/rest/api/1.0",
+		token:    token,
+		projects: projects,
+		label:    httpclient.HostLabel(baseURL, "bitbucket-server", nil),
+	}
+}
+
+func (p *ServerProvider) Name() string {
+	return "bitbucket-server"
+}
+
+func (p *ServerProvider) SetRepoFilter(f core.RepoFilter) {
+	p.filter = f
+}
+
+type serverUser struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+type serverRepo struct {
+	Slug     string `json:"slug"`
+	Name     string `json:"name"`
+	Public   bool   `json:"public"`
+	Archived bool   `json:"archived"`
+	Origin   *struct {
+		Slug string `json:"slug"`
+	} `json:"origin"`
+	Project struct {
+		Key string `json:"key"`
+	} `json:"project"`
+	Links struct {
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"`
+	} `json:"links"`
+}
+
+type serverPullRequest struct {
+	State string `json:"state"`
+}
+
+type serverCommit struct {
+	AuthorTimestamp int64 `json:"authorTimestamp"`
+	Author          struct {
+		Name         string `json:"name"`
+		EmailAddress string `json:"emailAddress"`
+	} `json:"author"`
+}
+
+type serverPage[T any] struct {
+	Values        []T  `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+func (p *ServerProvider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
+	user, err := p.fetchUser(ctx, handle)
+	if err != nil {
+		return core.DevStats{}, fmt.Errorf("bitbucket-server: fetch user: %w", err)
+	}
+
+	projects := p.projects
+	if len(projects) == 0 {
+		projects = []string{"~" + user.Slug}
+	}
+
+	var repos []serverRepo
+	for _, key := range projects {
+		projectRepos, err := p.fetchRepos(ctx, key)
+		if err != nil {
+			return core.DevStats{}, fmt.Errorf("bitbucket-server: fetch repos for project %s: %w", key, err)
+		}
+		repos = append(repos, projectRepos...)
+	}
+	repos, skipped := p.filterRepos(repos)
+
+	publicRepos := 0
+	privateRepos := 0
+	for _, r := range repos {
+		if r.Public {
+			publicRepos++
+		} else {
+			privateRepos++
+		}
+	}
+
+	var diag core.Diagnostics
+	diag.Supplied(p.label, core.FieldRepos)
+	diag.Unsupported(p.label, core.FieldStars, core.FieldFollowers, core.FieldContributedRepos, core.FieldJoined, core.FieldLanguages, core.FieldIssues, core.FieldReviews, core.FieldPackages)
+
+	prStats := core.PRStats{}
+	contribs := make(map[time.Time]int)
+	totalCommits := 0
+	since := time.Now().UTC().AddDate(-1, 0, 0)
+	prFailures := 0
+	commitFailures := 0
+
+	for _, r := range repos {
+		repoPRs, err := p.fetchPRStats(ctx, r, user.Slug)
+		if err != nil {
+			log.Printf("bitbucket-server: fetchPRStats error for %s/%s: %v", r.Project.Key, r.Slug, err)
+			prFailures++
+		} else {
+			prStats.Open += repoPRs.Open
+			prStats.Merged += repoPRs.Merged
+			prStats.Closed += repoPRs.Closed
+		}
+
+		days, err := p.fetchCommitsPerDay(ctx, r, user, since)
+		if err != nil {
+			log.Printf("bitbucket-server: fetchCommitsPerDay error for %s/%s: %v", r.Project.Key, r.Slug, err)
+			commitFailures++
+			continue
+		}
+		for day, count := range days {
+			contribs[day] += count
+			totalCommits += count
+		}
+	}
+
+	diag.RecordPartial(p.label, core.FieldPullRequests, prFailures, len(repos))
+	diag.RecordPartial(p.label, core.FieldContributions, commitFailures, len(repos))
+
+	currentStreak, longestStreak := core.ComputeStreaks(contribs)
+
+	name := user.DisplayName
+	if name == "" {
+		name = user.Name
+	}
+
+	stats := core.DevStats{
+		Identity: core.Identity{
+			Name:     name,
+			Username: user.Slug,
+			Handles:  []string{p.label + ": " + user.Slug},
+		},
+		Totals: core.Totals{
+			PublicRepos:     publicRepos,
+			PrivateRepos:    privateRepos,
+			Commits:         totalCommits,
+			CurrentStreak:   currentStreak,
+			LongestStreak:   longestStreak,
+			CommitsThisWeek: core.CommitsThisWeek(contribs),
+			FilteredRepos:   len(skipped),
+		},
+		Activity: core.Activity{
+			ContributionsPerDay: contribs,
+			PullRequests:        prStats,
+		},
+		Repositories: p.repositories(repos),
+		Diagnostics:  diag,
+	}
+
+	return stats, nil
+}
+
+func (p *ServerProvider) fetchUser(ctx context.Context, slug string) (*serverUser, error) {
+	endpoint := fmt.Sprintf("%s/users/%s", p.baseURL, url.PathEscape(slug))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	p.applyAuth(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, fmt.Errorf("unauthorized (401), check DEV_METRICS_BITBUCKET_SERVER_TOKEN")
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("user %q not found", slug)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	var u serverUser
+	if err := json.NewDecoder(resp.Body).Decode(&u); err != nil {
+		return nil, fmt.Errorf("decode user response: %w", err)
+	}
+
+	return &u, nil
+}
+
+func (p *ServerProvider) fetchRepos(ctx context.Context, projectKey string) ([]serverRepo, error) {
+	var all []serverRepo
+
+	path := fmt.Sprintf("/projects/%s/repos", url.PathEscape(projectKey))
+	err := getServerPages(ctx, p, path, nil, func(page []serverRepo) bool {
+		all = append(all, page...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
+
+func (p *ServerProvider) filterRepos(repos []serverRepo) (kept, skipped []serverRepo) {
+	for _, r := range repos {
+		meta := core.RepoMeta{
+			Name:     r.Slug,
+			FullName: r.Project.Key + "/" + r.Slug,
+			Fork:     r.Origin != nil,
+			Archived: r.Archived,
+		}
+		if p.filter.Keep(meta) {
+			kept = append(kept, r)
+		} else {
+			skipped = append(skipped, r)
+		}
+	}
+	return kept, skipped
+}
+
+func (p *ServerProvider) repositories(repos []serverRepo) []core.Repository {
+	out := make([]core.Repository, 0, len(repos))
+	for _, r := range repos {
+		repo := core.Repository{
+			Provider: p.label,
+			Name:     r.Project.Key + "/" + r.Slug,
+			Private:  !r.Public,
+		}
+		if len(r.Links.Self) > 0 {
+			repo.URL = r.Links.Self[0].Href
+		}
+		out = append(out, repo)
+	}
+	return out
+}
+
+func (p *ServerProvider) fetchPRStats(ctx context.Context, repo serverRepo, userSlug string) (core.PRStats, error) {
+	var stats core.PRStats
+
+	params := url.Values{}
+	params.Set("state", "ALL")
+	params.Set("role.1", "AUTHOR")
+	params.Set("username.1", userSlug)
+
+	path := fmt.Sprintf("/projects/%s/repos/%s/pull-requests", url.PathEscape(repo.Project.Key), url.PathEscape(repo.Slug))
+	err := getServerPages(ctx, p, path, params, func(page []serverPullRequest) bool {
+		for _, pr := range page {
+			switch pr.State {
+			case "OPEN":
+				stats.Open++
+			case "MERGED":
+				stats.Merged++
+			case "DECLINED":
+				stats.Closed++
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return core.PRStats{}, err
+	}
+
+	return stats, nil
+}
+
+func (p *ServerProvider) fetchCommitsPerDay(ctx context.Context, repo serverRepo, user *serverUser, since time.Time) (map[time.Time]int, error) {
+	m := make(map[time.Time]int)
+
+	path := fmt.Sprintf("/projects/%s/repos/%s/commits", url.PathEscape(repo.Project.Key), url.PathEscape(repo.Slug))
+	err := getServerPages(ctx, p, path, nil, func(page []serverCommit) bool {
+		return core.CountCommitPage(m, page, since, func(c serverCommit) (time.Time, bool) {
+			return time.UnixMilli(c.AuthorTimestamp), isServerAuthor(c, user)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func getServerPages[T any](ctx context.Context, p *ServerProvider, path string, params url.Values, visit func([]T) bool) error {
+	start := 0
+
+	for {
+		query := url.Values{}
+		for k, v := range params {
+			query[k] = v
+		}
+		query.Set("limit", fmt.Sprint(serverPageLimit))
+		query.Set("start", fmt.Sprint(start))
+
+		endpoint := p.baseURL + path + "?" + query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return fmt.Errorf("new request: %w", err)
+		}
+		p.applyAuth(req)
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("do request: %w", err)
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			resp.Body.Close()
+			return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+		}
+
+		var page serverPage[T]
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+			resp.Body.Close()
+			return fmt.Errorf("decode response from %s: %w", endpoint, err)
+		}
+		resp.Body.Close()
+
+		if !visit(page.Values) || page.IsLastPage || len(page.Values) == 0 {
+			return nil
+		}
+		start = page.NextPageStart
+	}
+}
+
+func isServerAuthor(c serverCommit, user *serverUser) bool {
+	if user.EmailAddress != "" && strings.EqualFold(c.Author.EmailAddress, user.EmailAddress) {
+		return true
+	}
+	return strings.EqualFold(c.Author.Name, user.Name) || strings.EqualFold(c.Author.Name, user.Slug)
+}
+
+func (p *ServerProvider) applyAuth(req *http.Request) {
+	req.Header.Set("Accept", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+}
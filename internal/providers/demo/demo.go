@@ -2,6 +2,7 @@ package demo
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/vukan322/devmetrics/internal/core"
@@ -26,6 +27,19 @@ func (d *DemoProvider) Fetch(ctx context.Context, handle string) (core.DevStats,
 		contribs[time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())] = 3 + i
 	}
 
+	repos := []core.Repository{
+		{Provider: "demo", Name: handle + "/devmetrics", Stars: 20, Languages: []core.LanguageStat{{Name: "Go", Bytes: 500000}}},
+		{Provider: "demo", Name: handle + "/dashboard", Stars: 8, Languages: []core.LanguageStat{{Name: "TypeScript", Bytes: 200000}, {Name: "Go", Bytes: 200000}}},
+		{Provider: "demo", Name: handle + "/dotfiles", Stars: 4, Languages: []core.LanguageStat{{Name: "Lua", Bytes: 100000}}},
+	}
+	for i := len(repos); i < 15; i++ {
+		repos = append(repos, core.Repository{
+			Provider: "demo",
+			Name:     fmt.Sprintf("%s/project-%02d", handle, i),
+			Private:  i >= 12,
+		})
+	}
+
 	return core.DevStats{
 		Identity: core.Identity{
 			Name:     "Demo Developer",
@@ -43,10 +57,11 @@ func (d *DemoProvider) Fetch(ctx context.Context, handle string) (core.DevStats,
 		Activity: core.Activity{
 			ContributionsPerDay: contribs,
 			TopLanguages: []core.LanguageStat{
-				{Name: "Go", Percentage: 70},
-				{Name: "TypeScript", Percentage: 20},
-				{Name: "Lua", Percentage: 10},
+				{Name: "Go", Bytes: 700000},
+				{Name: "TypeScript", Bytes: 200000},
+				{Name: "Lua", Bytes: 100000},
 			},
 		},
+		Repositories: repos,
 	}, nil
 }
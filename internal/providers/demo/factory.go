@@ -0,0 +1,20 @@
+package demo
+
+import (
+	"github.com/vukan322/devmetrics/internal/providers"
+)
+
+func Factory() providers.Factory {
+	return providers.Factory{
+		Name:   "demo",
+		Title:  "demo",
+		Prefix: "DEV_METRICS_DEMO",
+		OptIn:  true,
+		Keys: []providers.Key{
+			{Name: "USER", Required: true, UserFallback: true, Usage: "handle shown on the card (defaults to -user)"},
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			return New(), cfg.Get("USER"), nil
+		},
+	}
+}
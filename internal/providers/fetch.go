@@ -0,0 +1,80 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vukan322/devmetrics/internal/core"
+)
+
+type Result struct {
+	Instance Instance
+	Stats    core.DevStats
+	Err      error
+	Elapsed  time.Duration
+}
+
+func FetchAll(ctx context.Context, instances []Instance) []Result {
+	results := make([]Result, len(instances))
+
+	var wg sync.WaitGroup
+	for i, inst := range instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = fetchOne(ctx, inst)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+func fetchOne(ctx context.Context, inst Instance) (res Result) {
+	res.Instance = inst
+
+	if inst.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, inst.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	defer func() {
+		res.Elapsed = time.Since(start)
+		if r := recover(); r != nil {
+			res.Stats = core.DevStats{}
+			res.Err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	res.Stats, res.Err = inst.Provider.Fetch(ctx, inst.Handle)
+	if res.Err != nil && ctx.Err() == context.DeadlineExceeded {
+		res.Err = fmt.Errorf("timed out after %s: %w", inst.Timeout, res.Err)
+	}
+
+	return res
+}
+
+func Merge(results []Result) (core.DevStats, []string) {
+	var (
+		stats core.DevStats
+		used  []string
+	)
+
+	for _, res := range results {
+		if res.Err != nil {
+			continue
+		}
+		if len(used) == 0 {
+			stats = res.Stats
+		} else {
+			stats = core.MergeStats(stats, res.Stats)
+		}
+		used = append(used, res.Instance.Label())
+	}
+
+	return stats, used
+}
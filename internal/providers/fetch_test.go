@@ -0,0 +1,112 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vukan322/devmetrics/internal/core"
+)
+
+type scriptedProvider struct {
+	name  string
+	delay time.Duration
+	fetch func(ctx context.Context) (core.DevStats, error)
+}
+
+func (p *scriptedProvider) Name() string { return p.name }
+
+func (p *scriptedProvider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
+	select {
+	case <-time.After(p.delay):
+	case <-ctx.Done():
+		return core.DevStats{}, ctx.Err()
+	}
+	if p.fetch != nil {
+		return p.fetch(ctx)
+	}
+
+	var diag core.Diagnostics
+	diag.Supplied(p.name, core.FieldRepos)
+	return core.DevStats{
+		Identity:    core.Identity{Username: handle, Handles: []string{p.name + ": " + handle}},
+		Totals:      core.Totals{PublicRepos: 1},
+		Diagnostics: diag,
+	}, nil
+}
+
+func instance(name string, timeout time.Duration, p Provider) Instance {
+	return Instance{
+		Factory:  Factory{Name: name, Title: name},
+		Index:    1,
+		Provider: p,
+		Handle:   "ada",
+		Timeout:  timeout,
+	}
+}
+
+func TestFetchAll(t *testing.T) {
+	instances := []Instance{
+		instance("slow", time.Second, &scriptedProvider{name: "slow", delay: 60 * time.Millisecond}),
+		instance("hung", 20*time.Millisecond, &scriptedProvider{name: "hung", delay: time.Hour}),
+		instance("broken", time.Second, &scriptedProvider{name: "broken", fetch: func(context.Context) (core.DevStats, error) {
+			panic("nil map")
+		}}),
+		instance("fast", time.Second, &scriptedProvider{name: "fast"}),
+		instance("medium", time.Second, &scriptedProvider{name: "medium", delay: 30 * time.Millisecond}),
+	}
+
+	start := time.Now()
+	results := FetchAll(context.Background(), instances)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("FetchAll took %s, want providers fetched concurrently", elapsed)
+	}
+
+	if len(results) != len(instances) {
+		t.Fatalf("got %d results, want %d", len(results), len(instances))
+	}
+	for i, res := range results {
+		if res.Instance.Factory.Name != instances[i].Factory.Name {
+			t.Errorf("result %d is %s, want %s", i, res.Instance.Factory.Name, instances[i].Factory.Name)
+		}
+	}
+
+	if err := results[1].Err; !errors.Is(err, context.DeadlineExceeded) || !strings.HasPrefix(err.Error(), "timed out after 20ms") {
+		t.Errorf("hung provider error = %v, want a timeout after 20ms", err)
+	}
+	if err := results[2].Err; err == nil || err.Error() != "panic: nil map" {
+		t.Errorf("broken provider error = %v, want the recovered panic", err)
+	}
+	for _, i := range []int{0, 3, 4} {
+		if results[i].Err != nil {
+			t.Errorf("%s provider error = %v", results[i].Instance.Factory.Name, results[i].Err)
+		}
+	}
+
+	stats, used := Merge(results)
+	if want := []string{"slow", "fast", "medium"}; !reflect.DeepEqual(used, want) {
+		t.Errorf("used = %v, want %v", used, want)
+	}
+	if want := []string{"slow: ada", "fast: ada", "medium: ada"}; !reflect.DeepEqual(stats.Identity.Handles, want) {
+		t.Errorf("handles = %v, want %v", stats.Identity.Handles, want)
+	}
+	if stats.Totals.PublicRepos != 3 {
+		t.Errorf("PublicRepos = %d, want 3", stats.Totals.PublicRepos)
+	}
+	if got, want := stats.Diagnostics.Sources(core.FieldRepos), []string{"slow", "fast", "medium"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("repos sources = %v, want %v", got, want)
+	}
+}
+
+func TestFetchAllParentCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := FetchAll(ctx, []Instance{instance("hung", time.Hour, &scriptedProvider{name: "hung", delay: time.Hour})})
+	if err := results[0].Err; !errors.Is(err, context.Canceled) || strings.HasPrefix(err.Error(), "timed out") {
+		t.Errorf("error = %v, want the parent cancellation", err)
+	}
+}
@@ -0,0 +1,22 @@
+package gerrit
+
+import (
+	"github.com/vukan322/devmetrics/internal/providers"
+)
+
+func Factory() providers.Factory {
+	return providers.Factory{
+		Name:     "gerrit",
+		Title:    "Gerrit",
+		Prefix:   "DEV_METRICS_GERRIT",
+		Multiple: true,
+		Keys: []providers.Key{
+			{Name: "URL", Required: true, Usage: "Gerrit base URL, e.g. https://go-review.googlesource.com"},
+			{Name: "USER", Required: true, Usage: "Gerrit username or email"},
+			{Name: "PASSWORD", Usage: "HTTP password for authenticated access"},
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			return New(cfg.Get("URL"), cfg.Get("USER"), cfg.Get("PASSWORD")), cfg.Get("USER"), nil
+		},
+	}
+}
@@ -0,0 +1,281 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vukan322/devmetrics/internal/core"
+	"github.com/vukan322/devmetrics/internal/httpclient"
+)
+
+const (
+	defaultUserAgent = "devmetrics/0.1"
+	pageSize         = 500
+	timestampLayout  = "2006-01-02 15:04:05.000000000"
+)
+
+var xssiPrefix = []byte(")]}'")
+
+type Provider struct {
+	client   *http.Client
+	baseURL  string
+	username string
+	password string
+	label    string
+}
+
+func New(baseURL, username, password string) *Provider {
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	return &Provider{
+		client:   httpclient.New(),
+		baseURL:  baseURL,
+		username: username,
+		password: password,
+		label:    httpclient.HostLabel(baseURL, "gerrit", nil),
+	}
+}
+
+func (p *Provider) Name() string {
+	return "gerrit"
+}
+
+type gerritAccount struct {
+	AccountID    int    `json:"_account_id"`
+	Name         string `json:"name"`
+	Username     string `json:"username"`
+	Email        string `json:"email"`
+	RegisteredOn string `json:"registered_on"`
+}
+
+type gerritChange struct {
+	Status      string                 `json:"status"`
+	Created     string                 `json:"created"`
+	Labels      map[string]gerritLabel `json:"labels"`
+	MoreChanges bool                   `json:"_more_changes"`
+}
+
+type gerritLabel struct {
+	All []gerritApproval `json:"all"`
+}
+
+type gerritApproval struct {
+	AccountID int    `json:"_account_id"`
+	Value     int    `json:"value"`
+	Date      string `json:"date"`
+}
+
+func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
+	account, err := p.fetchAccount(ctx, handle)
+	if err != nil {
+		return core.DevStats{}, fmt.Errorf("gerrit: fetch account: %w", err)
+	}
+
+	owned, err := p.fetchChanges(ctx, fmt.Sprintf("owner:%s", handle))
+	if err != nil {
+		return core.DevStats{}, fmt.Errorf("gerrit: fetch owned changes: %w", err)
+	}
+
+	prStats := core.PRStats{}
+	contribs := make(map[time.Time]int)
+	totalChanges := 0
+	since := time.Now().UTC().AddDate(-1, 0, 0)
+
+	for _, c := range owned {
+		switch c.Status {
+		case "NEW":
+			prStats.Open++
+		case "MERGED":
+			prStats.Merged++
+		case "ABANDONED":
+			prStats.Closed++
+		}
+
+		created, err := parseTimestamp(c.Created)
+		if err != nil || created.Before(since) {
+			continue
+		}
+		contribs[time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, time.UTC)]++
+		totalChanges++
+	}
+
+	reviewQuery := fmt.Sprintf("label:Code-Review=any,user=%s -owner:%s after:%s", handle, handle, since.Format("2006-01-02"))
+	reviewed, err := p.fetchChanges(ctx, reviewQuery, "DETAILED_LABELS")
+	if err != nil {
+		log.Printf("gerrit: fetch reviewed changes error for %s: %v", handle, err)
+	}
+	reviews := countReviews(reviewed, account.AccountID, since)
+
+	var diag core.Diagnostics
+	diag.Supplied(p.label, core.FieldContributions, core.FieldPullRequests, core.FieldJoined)
+	diag.Record(p.label, core.FieldReviews, err)
+	diag.Unsupported(p.label, core.FieldRepos, core.FieldStars, core.FieldFollowers, core.FieldContributedRepos, core.FieldLanguages, core.FieldIssues, core.FieldPackages)
+
+	var joined time.Time
+	if registered, err := parseTimestamp(account.RegisteredOn); err == nil {
+		joined = registered
+	}
+
+	username := account.Username
+	if username == "" {
+		username = handle
+	}
+
+	currentStreak, longestStreak := core.ComputeStreaks(contribs)
+
+	stats := core.DevStats{
+		Identity: core.Identity{
+			Name:     account.Name,
+			Username: username,
+			Handles:  []string{p.label + ": " + username},
+		},
+		Totals: core.Totals{
+			Joined:          joined,
+			Commits:         totalChanges,
+			CurrentStreak:   currentStreak,
+			LongestStreak:   longestStreak,
+			CommitsThisWeek: core.CommitsThisWeek(contribs),
+			Reviews:         reviews,
+		},
+		Activity: core.Activity{
+			ContributionsPerDay: contribs,
+			PullRequests:        prStats,
+		},
+		Diagnostics: diag,
+	}
+
+	return stats, nil
+}
+
+func (p *Provider) fetchAccount(ctx context.Context, handle string) (*gerritAccount, error) {
+	var account gerritAccount
+	if err := p.get(ctx, "/accounts/"+url.PathEscape(handle)+"/detail", nil, &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (p *Provider) fetchChanges(ctx context.Context, query string, options ...string) ([]gerritChange, error) {
+	var all []gerritChange
+
+	for start := 0; ; start += pageSize {
+		params := url.Values{}
+		params.Set("q", query)
+		params.Set("n", fmt.Sprint(pageSize))
+		params.Set("S", fmt.Sprint(start))
+		for _, o := range options {
+			params.Add("o", o)
+		}
+
+		var page []gerritChange
+		if err := p.get(ctx, "/changes/", params, &page); err != nil {
+			return nil, err
+		}
+
+		all = append(all, page...)
+
+		if len(page) == 0 || !page[len(page)-1].MoreChanges {
+			break
+		}
+	}
+
+	return all, nil
+}
+
+// countReviews counts changes carrying a Code-Review vote by the account cast
+// on or after since. The after: search operator only bounds the change's last
+// update, so older votes on recently touched changes are filtered here.
+func countReviews(changes []gerritChange, accountID int, since time.Time) int {
+	count := 0
+	for _, c := range changes {
+		for _, a := range c.Labels["Code-Review"].All {
+			if a.AccountID != accountID || a.Value == 0 {
+				continue
+			}
+			if voted, err := parseTimestamp(a.Date); err == nil && !voted.Before(since) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+func (p *Provider) get(ctx context.Context, path string, params url.Values, out any) error {
+	endpoint := p.baseURL
+	if p.password != "" {
+		endpoint += "/a"
+	}
+	endpoint += path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	p.applyAuth(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("unauthorized (401), check DEV_METRICS_GERRIT_USER and DEV_METRICS_GERRIT_PASSWORD")
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("not found: %s", endpoint)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	body, err := stripXSSI(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decode response from %s: %w", endpoint, err)
+	}
+
+	return nil
+}
+
+func stripXSSI(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimPrefix(data, xssiPrefix), nil
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty timestamp")
+	}
+	return time.ParseInLocation(timestampLayout, s, time.UTC)
+}
+
+func (p *Provider) applyAuth(req *http.Request) {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", defaultUserAgent)
+	if p.username == "" || p.password == "" {
+		return
+	}
+
+	creds := p.username + ":" + p.password
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(creds)))
+}
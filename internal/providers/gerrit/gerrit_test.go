@@ -0,0 +1,105 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vukan322/devmetrics/internal/core"
+)
+
+func gerritTime(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
+
+func TestFetch(t *testing.T) {
+	now := time.Now().UTC()
+	recent := gerritTime(now.AddDate(0, 0, -10))
+	old := gerritTime(now.AddDate(-2, 0, 0))
+
+	vote := func(account, value int, date string) string {
+		return fmt.Sprintf(`{"_account_id": %d, "value": %d, "date": %q}`, account, value, date)
+	}
+	reviewed := func(approvals ...string) string {
+		return `{"status": "NEW", "labels": {"Code-Review": {"all": [` + strings.Join(approvals, ",") + `]}}}`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, ")]}'\n")
+
+		if r.URL.Path == "/accounts/ada/detail" {
+			io.WriteString(w, `{"_account_id": 1000, "name": "Ada", "username": "ada", "registered_on": "2019-04-02 10:00:00.000000000"}`)
+			return
+		}
+
+		q := r.URL.Query()
+		switch {
+		case q.Get("q") == "owner:ada" && q.Get("S") == "0":
+			fmt.Fprintf(w, `[{"status": "NEW", "created": %q}, {"status": "MERGED", "created": %q}, {"status": "MERGED", "created": %q, "_more_changes": true}]`, recent, recent, old)
+		case q.Get("q") == "owner:ada":
+			fmt.Fprintf(w, `[{"status": "ABANDONED", "created": %q}]`, recent)
+		case strings.HasPrefix(q.Get("q"), "label:Code-Review=any,user=ada -owner:ada after:"):
+			if q.Get("o") != "DETAILED_LABELS" {
+				t.Errorf("reviews query options = %q, want DETAILED_LABELS", q["o"])
+			}
+			io.WriteString(w, "["+strings.Join([]string{
+				reviewed(vote(1000, 2, recent)),
+				reviewed(vote(2000, 1, recent), vote(1000, -1, recent)),
+				reviewed(vote(1000, 1, old)),
+				reviewed(vote(1000, 0, recent)),
+				reviewed(vote(2000, 2, recent)),
+			}, ",")+"]")
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			io.WriteString(w, "[]")
+		}
+	}))
+	defer srv.Close()
+
+	p := New(srv.URL, "", "")
+	p.client = srv.Client()
+
+	stats, err := p.Fetch(context.Background(), "ada")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if want := (core.PRStats{Open: 1, Merged: 2, Closed: 1}); stats.Activity.PullRequests != want {
+		t.Errorf("PullRequests = %+v, want %+v", stats.Activity.PullRequests, want)
+	}
+	if stats.Totals.Commits != 3 {
+		t.Errorf("Commits = %d, want the 3 changes created in the last year", stats.Totals.Commits)
+	}
+	if stats.Totals.Reviews != 2 {
+		t.Errorf("Reviews = %d, want 2", stats.Totals.Reviews)
+	}
+	if want := time.Date(2019, 4, 2, 10, 0, 0, 0, time.UTC); !stats.Totals.Joined.Equal(want) {
+		t.Errorf("Joined = %s, want %s", stats.Totals.Joined, want)
+	}
+	if stats.Identity.Username != "ada" || stats.Identity.Name != "Ada" {
+		t.Errorf("Identity = %+v", stats.Identity)
+	}
+}
+
+func TestStripXSSI(t *testing.T) {
+	tests := map[string]string{
+		")]}'\n{\"a\": 1}": "\n{\"a\": 1}",
+		"{\"a\": 1}":       "{\"a\": 1}",
+		"":                 "",
+	}
+
+	for in, want := range tests {
+		got, err := stripXSSI(strings.NewReader(in))
+		if err != nil {
+			t.Fatalf("stripXSSI(%q): %v", in, err)
+		}
+		if string(got) != want {
+			t.Errorf("stripXSSI(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package gitea
+
+import (
+	"github.com/vukan322/devmetrics/internal/providers"
+)
+
+func Factory() providers.Factory {
+	return providers.Factory{
+		Name:     "gitea",
+		Title:    "Gitea",
+		Prefix:   "DEV_METRICS_GITEA",
+		Multiple: true,
+		Keys: []providers.Key{
+			{Name: "USER", Required: true, Usage: "Gitea/Forgejo username"},
+			{Name: "TOKEN", Usage: "access token, needed for private repositories"},
+			{Name: "URL", Default: defaultBaseURL, Usage: "instance URL, e.g. https://codeberg.org"},
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			return New(cfg.Get("URL"), cfg.Get("TOKEN")), cfg.Get("USER"), nil
+		},
+	}
+}
@@ -0,0 +1,453 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vukan322/devmetrics/internal/core"
+	"github.com/vukan322/devmetrics/internal/httpclient"
+	"github.com/vukan322/devmetrics/internal/languages"
+)
+
+const (
+	defaultBaseURL      = "https://codeberg.org"
+	defaultUserAgent    = "devmetrics/0.1"
+	pageLimit           = 50
+	languageConcurrency = 8
+)
+
+type Provider struct {
+	client  *http.Client
+	baseURL string
+	token   string
+	label   string
+	filter  core.RepoFilter
+}
+
+func New(baseURL, token string) *Provider {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	return &Provider{
+		client:  httpclient.New(),
+		baseURL: httpclient.APIURL(baseURL, "/api/v1"),
+		token:   token,
+		label:   httpclient.HostLabel(baseURL, "gitea", map[string]string{"codeberg.org": "codeberg", "gitea.com": "gitea"}),
+	}
+}
+
+func (p *Provider) Name() string {
+	return "gitea"
+}
+
+func (p *Provider) SetRepoFilter(f core.RepoFilter) {
+	p.filter = f
+}
+
+type giteaUser struct {
+	ID        int       `json:"id"`
+	Login     string    `json:"login"`
+	FullName  string    `json:"full_name"`
+	AvatarURL string    `json:"avatar_url"`
+	Followers int       `json:"followers_count"`
+	Following int       `json:"following_count"`
+	Created   time.Time `json:"created"`
+}
+
+type giteaRepo struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	FullName string   `json:"full_name"`
+	Private  bool     `json:"private"`
+	Stars    int      `json:"stars_count"`
+	Language string   `json:"language"`
+	Fork     bool     `json:"fork"`
+	Archived bool     `json:"archived"`
+	Mirror   bool     `json:"mirror"`
+	Template bool     `json:"template"`
+	Topics   []string `json:"topics"`
+	HTMLURL  string   `json:"html_url"`
+	Original string   `json:"original_url"`
+	Parent   *struct {
+		HTMLURL string `json:"html_url"`
+	} `json:"parent"`
+	Owner struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+type giteaHeatmapEntry struct {
+	Timestamp     int64 `json:"timestamp"`
+	Contributions int   `json:"contributions"`
+}
+
+type giteaLanguages map[string]int64
+
+func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
+	user, err := p.fetchUser(ctx, handle)
+	if err != nil {
+		return core.DevStats{}, fmt.Errorf("gitea: fetch user: %w", err)
+	}
+
+	repos, err := p.fetchRepos(ctx, user.Login)
+	if err != nil {
+		return core.DevStats{}, fmt.Errorf("gitea: fetch repos: %w", err)
+	}
+	repos, skipped := p.filterRepos(repos)
+
+	publicCount := 0
+	privateCount := 0
+	totalStars := 0
+
+	for _, r := range repos {
+		if r.Private {
+			privateCount++
+		} else {
+			publicCount++
+		}
+		totalStars += r.Stars
+	}
+
+	var diag core.Diagnostics
+	diag.Supplied(p.label, core.FieldRepos, core.FieldStars, core.FieldFollowers, core.FieldJoined)
+	diag.Unsupported(p.label, core.FieldContributedRepos, core.FieldIssues, core.FieldPullRequests, core.FieldReviews, core.FieldPackages)
+
+	langs, repoLanguages, err := p.computeLanguages(ctx, repos)
+	if err == nil || len(langs) > 0 {
+		diag.Supplied(p.label, core.FieldLanguages)
+	}
+	if err != nil {
+		diag.Failed(p.label, core.FieldLanguages, err)
+	}
+
+	contribs, err := p.fetchHeatmap(ctx, user.Login)
+	if err != nil {
+		log.Printf("gitea: fetchHeatmap error for %s: %v", user.Login, err)
+		contribs = make(map[time.Time]int)
+	}
+	diag.Record(p.label, core.FieldContributions, err)
+
+	totalContribs := 0
+	for _, c := range contribs {
+		totalContribs += c
+	}
+
+	currentStreak, longestStreak := core.ComputeStreaks(contribs)
+
+	avatarData, err := httpclient.FetchAvatar(ctx, p.client, user.AvatarURL)
+	if err != nil {
+		avatarData = ""
+	}
+
+	stats := core.DevStats{
+		Identity: core.Identity{
+			Name:     core.DisplayName(user.FullName, user.Login),
+			Username: user.Login,
+			Avatar:   avatarData,
+			Handles:  []string{p.label + ": " + user.Login},
+		},
+		Totals: core.Totals{
+			PublicRepos:     publicCount,
+			PrivateRepos:    privateCount,
+			Stars:           totalStars,
+			Followers:       user.Followers,
+			Following:       user.Following,
+			Joined:          user.Created,
+			Commits:         totalContribs,
+			CurrentStreak:   currentStreak,
+			LongestStreak:   longestStreak,
+			CommitsThisWeek: core.CommitsThisWeek(contribs),
+			FilteredRepos:   len(skipped),
+		},
+		Activity: core.Activity{
+			ContributionsPerDay: contribs,
+			TopLanguages:        langs,
+		},
+		Repositories: p.repositories(repos, repoLanguages),
+		Diagnostics:  diag,
+	}
+
+	return stats, nil
+}
+
+func (p *Provider) fetchUser(ctx context.Context, handle string) (*giteaUser, error) {
+	endpoint := fmt.Sprintf("%s/users/%s", p.baseURL, url.PathEscape(handle))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gitea: new user request: %w", err)
+	}
+	p.applyHeaders(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gitea: do user request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("gitea: user %q not found", handle)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("gitea: fetch user: unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	var u giteaUser
+	if err := json.NewDecoder(resp.Body).Decode(&u); err != nil {
+		return nil, fmt.Errorf("gitea: decode user response: %w", err)
+	}
+
+	return &u, nil
+}
+
+func (p *Provider) fetchRepos(ctx context.Context, login string) ([]giteaRepo, error) {
+	var all []giteaRepo
+	page := 1
+
+	for {
+		endpoint := fmt.Sprintf(
+			"%s/users/%s/repos?limit=%d&page=%d",
+			p.baseURL,
+			url.PathEscape(login),
+			pageLimit,
+			page,
+		)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("gitea: new repos request: %w", err)
+		}
+		p.applyHeaders(req)
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("gitea: do repos request: %w", err)
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			resp.Body.Close()
+			return nil, fmt.Errorf("gitea: fetch repos: unexpected status %d from %s", resp.StatusCode, endpoint)
+		}
+
+		var pageRepos []giteaRepo
+		if err := json.NewDecoder(resp.Body).Decode(&pageRepos); err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("gitea: decode repos response: %w", err)
+		}
+		resp.Body.Close()
+
+		if len(pageRepos) == 0 {
+			break
+		}
+
+		all = append(all, pageRepos...)
+		if len(pageRepos) < pageLimit {
+			break
+		}
+		page++
+	}
+
+	return all, nil
+}
+
+func (p *Provider) fetchHeatmap(ctx context.Context, login string) (map[time.Time]int, error) {
+	endpoint := fmt.Sprintf("%s/users/%s/heatmap", p.baseURL, url.PathEscape(login))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gitea: new heatmap request: %w", err)
+	}
+	p.applyHeaders(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gitea: do heatmap request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("gitea: fetch heatmap: unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	var entries []giteaHeatmapEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("gitea: decode heatmap response: %w", err)
+	}
+
+	m := make(map[time.Time]int)
+	for _, e := range entries {
+		if e.Contributions <= 0 {
+			continue
+		}
+		t := time.Unix(e.Timestamp, 0).UTC()
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		m[day] += e.Contributions
+	}
+
+	return m, nil
+}
+
+func (p *Provider) computeLanguages(ctx context.Context, repos []giteaRepo) ([]core.LanguageStat, map[int]map[string]int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		counts    = map[string]int64{}
+		perRepo   = make(map[int]map[string]int64, len(repos))
+		succeeded int
+	)
+
+	jobs := make(chan giteaRepo)
+
+	for range min(languageConcurrency, len(repos)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range jobs {
+				langs, err := p.fetchRepoLanguages(ctx, r.Owner.Login, r.Name)
+
+				mu.Lock()
+				if err != nil {
+					if ctx.Err() == nil {
+						log.Printf("gitea: fetch languages failed for repo %s: %v", r.FullName, err)
+					}
+					var rl *httpclient.RateLimitError
+					if errors.As(err, &rl) {
+						cancel()
+					}
+				} else {
+					repoCounts := make(map[string]int64, len(langs))
+					for name, bytes := range langs {
+						repoCounts[languages.Normalize(name)] += bytes
+						counts[languages.Normalize(name)] += bytes
+					}
+					perRepo[r.ID] = repoCounts
+					succeeded++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, r := range repos {
+		select {
+		case jobs <- r:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	failed := len(repos) - succeeded
+	var err error
+	if failed > 0 {
+		err = fmt.Errorf("languages unavailable for %d of %d repos", failed, len(repos))
+	}
+
+	return languageStats(counts), perRepo, err
+}
+
+func languageStats(counts map[string]int64) []core.LanguageStat {
+	langStats := make([]core.LanguageStat, 0, len(counts))
+	for name, v := range counts {
+		langStats = append(langStats, core.LanguageStat{
+			Name:  name,
+			Bytes: v,
+			Color: languages.Color(name),
+		})
+	}
+	return langStats
+}
+
+func (p *Provider) repositories(repos []giteaRepo, repoLanguages map[int]map[string]int64) []core.Repository {
+	out := make([]core.Repository, 0, len(repos))
+	for _, r := range repos {
+		source := r.Original
+		if source == "" && r.Parent != nil {
+			source = r.Parent.HTMLURL
+		}
+
+		out = append(out, core.Repository{
+			Provider:  p.label,
+			Name:      r.FullName,
+			URL:       r.HTMLURL,
+			Private:   r.Private,
+			Stars:     r.Stars,
+			Source:    source,
+			Languages: languageStats(repoLanguages[r.ID]),
+		})
+	}
+	return out
+}
+
+func (p *Provider) filterRepos(repos []giteaRepo) (kept, skipped []giteaRepo) {
+	for _, r := range repos {
+		meta := core.RepoMeta{
+			Name:     r.Name,
+			FullName: r.FullName,
+			Fork:     r.Fork,
+			Archived: r.Archived,
+			Mirror:   r.Mirror,
+			Template: r.Template,
+			Topics:   r.Topics,
+		}
+		if p.filter.Keep(meta) {
+			kept = append(kept, r)
+		} else {
+			skipped = append(skipped, r)
+		}
+	}
+	return kept, skipped
+}
+
+func (p *Provider) fetchRepoLanguages(ctx context.Context, owner, repo string) (giteaLanguages, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/languages", p.baseURL, url.PathEscape(owner), url.PathEscape(repo))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gitea: new languages request: %w", err)
+	}
+	p.applyHeaders(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gitea: do languages request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return giteaLanguages{}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("gitea: fetch languages: unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	var langs giteaLanguages
+	if err := json.NewDecoder(resp.Body).Decode(&langs); err != nil {
+		return nil, fmt.Errorf("gitea: decode languages response: %w", err)
+	}
+
+	return langs, nil
+}
+
+func (p *Provider) applyHeaders(req *http.Request) {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", defaultUserAgent)
+	if p.token != "" {
+		req.Header.Set("Authorization", "token "+p.token)
+	}
+}
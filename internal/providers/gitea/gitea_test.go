@@ -0,0 +1,79 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestComputeLanguagesBoundsConcurrency(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		inFlight int
+		peak     int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/api/v1/repos/ada/broken/languages":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		case "/api/v1/repos/ada/site/languages":
+			io.WriteString(w, `{"HTML": 500, "golang": 20}`)
+		default:
+			io.WriteString(w, `{"Go": 100}`)
+		}
+	}))
+	defer srv.Close()
+
+	repos := []giteaRepo{{ID: 1, Name: "site"}, {ID: 2, Name: "broken"}}
+	for i := 3; i <= 24; i++ {
+		repos = append(repos, giteaRepo{ID: i, Name: fmt.Sprintf("r%d", i)})
+	}
+	for i := range repos {
+		repos[i].Owner.Login = "ada"
+		repos[i].FullName = "ada/" + repos[i].Name
+	}
+
+	p := New(srv.URL, "")
+	p.client = srv.Client()
+
+	langs, perRepo, err := p.computeLanguages(context.Background(), repos)
+	if err == nil || err.Error() != "languages unavailable for 1 of 24 repos" {
+		t.Errorf("error = %v, want 1 of 24 repos failing", err)
+	}
+
+	got := make(map[string]int64)
+	for _, l := range langs {
+		got[l.Name] = l.Bytes
+	}
+	if want := map[string]int64{"Go": 2220, "HTML": 500}; !reflect.DeepEqual(got, want) {
+		t.Errorf("language bytes = %v, want %v", got, want)
+	}
+	if len(perRepo) != 23 || !reflect.DeepEqual(perRepo[1], map[string]int64{"HTML": 500, "Go": 20}) {
+		t.Errorf("per repo languages = %d repos, site = %v", len(perRepo), perRepo[1])
+	}
+
+	if peak > languageConcurrency {
+		t.Errorf("peak concurrent requests = %d, want at most %d", peak, languageConcurrency)
+	}
+	if peak < 2 {
+		t.Errorf("peak concurrent requests = %d, want lookups to run in parallel", peak)
+	}
+}
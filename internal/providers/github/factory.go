@@ -0,0 +1,46 @@
+package github
+
+import (
+	"log"
+
+	"github.com/vukan322/devmetrics/internal/providers"
+)
+
+func Factory() providers.Factory {
+	return providers.Factory{
+		Name:   "github",
+		Title:  "GitHub",
+		Prefix: "DEV_METRICS_GITHUB",
+		Keys: []providers.Key{
+			{Name: "USER", Required: true, UserFallback: true, Usage: "GitHub username (defaults to -user)"},
+			{Name: "TOKEN", Aliases: []string{"DEV_METRICS_TOKEN"}, Usage: "personal access token; unauthenticated requests are rate limited"},
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			if cfg.Get("TOKEN") == "" {
+				log.Println("warning: DEV_METRICS_TOKEN not set, using unauthenticated GitHub API (rate limited)")
+			}
+			return New(cfg.Get("TOKEN")), cfg.Get("USER"), nil
+		},
+	}
+}
+
+func EnterpriseFactory() providers.Factory {
+	return providers.Factory{
+		Name:     "github-enterprise",
+		Title:    "GitHub Enterprise",
+		Prefix:   "DEV_METRICS_GITHUB_ENTERPRISE",
+		Multiple: true,
+		Keys: []providers.Key{
+			{Name: "URL", Required: true, Usage: "GitHub Enterprise Server host, e.g. https://github.example.com"},
+			{Name: "TOKEN", Usage: "personal access token for the enterprise host"},
+			{Name: "USER", Required: true, UserFallback: true, Usage: "enterprise username (defaults to -user)"},
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			p, err := NewEnterprise(cfg.Get("URL"), cfg.Get("TOKEN"))
+			if err != nil {
+				return nil, "", err
+			}
+			return p, cfg.Get("USER"), nil
+		},
+	}
+}
@@ -3,43 +3,84 @@ package github
 import (
 	"bytes"
 	"context"
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
-	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/vukan322/devmetrics/internal/core"
+	"github.com/vukan322/devmetrics/internal/httpclient"
+	"github.com/vukan322/devmetrics/internal/languages"
 )
 
 const (
-	defaultBaseURL   = "https://api.github.com"
-	defaultUserAgent = "devmetrics/0.1"
+	defaultBaseURL      = "https://api.github.com"
+	defaultUserAgent    = "devmetrics/0.1"
+	languageConcurrency = 8
 )
 
 type Provider struct {
-	client  *http.Client
-	baseURL string
-	token   string
+	client     *http.Client
+	baseURL    string
+	graphqlURL string
+	token      string
+	label      string
+	enterprise bool
+	filter     core.RepoFilter
 }
 
 func New(token string) *Provider {
 	return &Provider{
-		client:  &http.Client{Timeout: 10 * time.Second},
-		baseURL: defaultBaseURL,
-		token:   token,
+		client:     httpclient.New(),
+		baseURL:    defaultBaseURL,
+		graphqlURL: defaultBaseURL + "/graphql",
+		token:      token,
+		label:      "github",
 	}
 }
 
+func NewEnterprise(host, token string) (*Provider, error) {
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("github: parse enterprise host %q: %w", host, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("github: enterprise host %q has no hostname", host)
+	}
+
+	root := u.Scheme + "://" + u.Host
+
+	return &Provider{
+		client:     httpclient.New(),
+		baseURL:    root + "/api/v3",
+		graphqlURL: root + "/api/graphql",
+		token:      token,
+		label:      httpclient.HostLabel(root, "github", nil),
+		enterprise: true,
+	}, nil
+}
+
 func (p *Provider) Name() string {
+	if p.enterprise {
+		return "github-enterprise"
+	}
 	return "github"
 }
 
+func (p *Provider) SetRepoFilter(f core.RepoFilter) {
+	p.filter = f
+}
+
 type githubUser struct {
 	Login       string    `json:"login"`
 	Name        string    `json:"name"`
@@ -51,10 +92,17 @@ type githubUser struct {
 }
 
 type githubRepo struct {
-	Name            string `json:"name"`
-	StargazersCount int    `json:"stargazers_count"`
-	Language        string `json:"language"`
-	Private         bool   `json:"private"`
+	Name            string   `json:"name"`
+	FullName        string   `json:"full_name"`
+	HTMLURL         string   `json:"html_url"`
+	StargazersCount int      `json:"stargazers_count"`
+	Language        string   `json:"language"`
+	Private         bool     `json:"private"`
+	Fork            bool     `json:"fork"`
+	Archived        bool     `json:"archived"`
+	IsTemplate      bool     `json:"is_template"`
+	MirrorURL       string   `json:"mirror_url"`
+	Topics          []string `json:"topics"`
 }
 
 func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
@@ -68,6 +116,10 @@ func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, err
 		return core.DevStats{}, fmt.Errorf("github: fetch repos: %w", err)
 	}
 
+	var diag core.Diagnostics
+	diag.Supplied(p.label, core.FieldRepos, core.FieldStars, core.FieldFollowers, core.FieldJoined)
+	diag.Unsupported(p.label, core.FieldReviews, core.FieldPackages)
+
 	if p.token != "" {
 		authUser, err := p.fetchAuthenticatedUser(ctx)
 		if err != nil {
@@ -83,28 +135,54 @@ func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, err
 		}
 	}
 
+	repos, skipped := p.filterRepos(repos)
+
 	contributedCount, err := p.fetchContributedRepos(ctx, handle)
 	if err != nil {
 		log.Printf("github: fetchContributedRepos error for %s: %v", handle, err)
 		contributedCount = 0
 	}
+	diag.Record(p.label, core.FieldContributedRepos, err)
 
 	issueStats, err := p.fetchIssueStats(ctx, handle)
 	if err != nil {
 		log.Printf("github: fetchIssueStats error for %s: %v", handle, err)
 		issueStats = core.IssueStats{}
 	}
+	diag.Record(p.label, core.FieldIssues, err)
 
 	prStats, err := p.fetchPRStats(ctx, handle)
 	if err != nil {
 		log.Printf("github: fetchPRStats error for %s: %v", handle, err)
 		prStats = core.PRStats{}
 	}
+	diag.Record(p.label, core.FieldPullRequests, err)
+
+	var heads map[string]githubRepoHead
+	if p.token != "" {
+		heads, err = p.fetchRepoHeads(ctx, handle)
+		if err != nil {
+			log.Printf("github: fetchRepoHeads error for %s: %v", handle, err)
+		}
+	}
+
+	repoLanguages, missing := headLanguages(repos, heads)
+	fetched, failedLanguages := p.fetchAllRepoLanguages(ctx, missing)
+	for name, counts := range fetched {
+		repoLanguages[name] = counts
+	}
+	diag.RecordPartial(p.label, core.FieldLanguages, failedLanguages, len(repos))
 
-	topLangs, totalLangs := computeLanguages(repos)
+	languageBytes := make(map[string]int64)
+	for _, counts := range repoLanguages {
+		for name, n := range counts {
+			languageBytes[name] += n
+		}
+	}
+	langs := computeLanguages(languageBytes)
 
 	privateCount := countPrivate(repos)
-	publicCount := user.PublicRepos
+	publicCount := user.PublicRepos - (len(skipped) - countPrivate(skipped))
 
 	contribs := make(map[time.Time]int)
 	totalCommits := 0
@@ -112,24 +190,18 @@ func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, err
 	longestStreak := 0
 	commitsThisWeek := 0
 
-	if p.token != "" {
+	if p.token == "" {
+		diag.Unsupported(p.label, core.FieldContributions)
+	} else {
 		cData, cTotal, err := p.fetchContributions(ctx, handle)
+		diag.Record(p.label, core.FieldContributions, err)
 		if err != nil {
 			log.Printf("github: fetchContributions error for %s: %v", handle, err)
 		} else {
 			contribs = cData
 			totalCommits = cTotal
 			currentStreak, longestStreak = core.ComputeStreaks(contribs)
-
-			today := time.Now().UTC()
-			startOfToday := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
-			weekStart := startOfToday.AddDate(0, 0, -6)
-
-			for day, count := range contribs {
-				if !day.Before(weekStart) && !day.After(startOfToday) {
-					commitsThisWeek += count
-				}
-			}
+			commitsThisWeek = core.CommitsThisWeek(contribs)
 		}
 	}
 
@@ -140,57 +212,40 @@ func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, err
 		Followers:        user.Followers,
 		Following:        user.Following,
 		ContributedRepos: contributedCount,
-		JoinedAgo:        formatJoinedAgo(user.CreatedAt),
-		TotalLanguages:   totalLangs,
+		Joined:           user.CreatedAt,
 		Commits:          totalCommits,
 		CurrentStreak:    currentStreak,
 		LongestStreak:    longestStreak,
 		CommitsThisWeek:  commitsThisWeek,
+		FilteredRepos:    len(skipped),
 	}
 
-	avatarData, err := fetchAvatar(ctx, p.client, user.AvatarURL)
+	avatarData, err := httpclient.FetchAvatar(ctx, p.client, user.AvatarURL)
 	if err != nil {
 		avatarData = ""
 	}
 
 	stats := core.DevStats{
 		Identity: core.Identity{
-			Name:     pickName(user),
+			Name:     core.DisplayName(user.Name, user.Login),
 			Username: user.Login,
 			Avatar:   avatarData,
-			Handles:  []string{"github: " + user.Login},
+			Handles:  []string{p.label + ": " + user.Login},
 		},
 		Totals: totals,
 		Activity: core.Activity{
 			ContributionsPerDay: contribs,
-			TopLanguages:        topLangs,
+			TopLanguages:        langs,
 			Issues:              issueStats,
 			PullRequests:        prStats,
 		},
+		Repositories: p.repositories(repos, repoLanguages, heads),
+		Diagnostics:  diag,
 	}
 
 	return stats, nil
 }
 
-func formatJoinedAgo(created time.Time) string {
-	years := time.Since(created).Hours() / 24 / 365
-	if years < 1 {
-		months := int(time.Since(created).Hours() / 24 / 30)
-		if months < 1 {
-			return "this month"
-		}
-		if months == 1 {
-			return "1 month ago"
-		}
-		return fmt.Sprintf("%d months ago", months)
-	}
-	y := int(years)
-	if y == 1 {
-		return "1 year ago"
-	}
-	return fmt.Sprintf("%d years ago", y)
-}
-
 func (p *Provider) searchCount(ctx context.Context, query string) (int, error) {
 	endpoint := fmt.Sprintf(
 		"%s/search/issues?q=%s&per_page=1",
@@ -357,7 +412,7 @@ func (p *Provider) fetchContributions(ctx context.Context, handle string) (map[t
 		return nil, 0, fmt.Errorf("github: marshal graphql body: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/graphql", bytes.NewReader(buf))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.graphqlURL, bytes.NewReader(buf))
 	if err != nil {
 		return nil, 0, fmt.Errorf("github: new graphql request: %w", err)
 	}
@@ -404,6 +459,158 @@ func (p *Provider) fetchContributions(ctx context.Context, handle string) (map[t
 	return m, coll.TotalCommitContributions, nil
 }
 
+type githubRepoHead struct {
+	SHA       string
+	Source    string
+	Languages map[string]int64
+}
+
+const repoHeadsQuery = `
+query($login: String!, $cursor: String) {
+  user(login: $login) {
+    repositories(first: 100, after: $cursor, ownerAffiliations: OWNER) {
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+      nodes {
+        nameWithOwner
+        mirrorUrl
+        parent {
+          url
+        }
+        defaultBranchRef {
+          target {
+            oid
+          }
+        }
+        languages(first: 100) {
+          edges {
+            size
+            node {
+              name
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func (p *Provider) fetchRepoHeads(ctx context.Context, handle string) (map[string]githubRepoHead, error) {
+	heads := make(map[string]githubRepoHead)
+	vars := map[string]any{"login": handle}
+
+	for {
+		var data struct {
+			User *struct {
+				Repositories struct {
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []struct {
+						NameWithOwner string `json:"nameWithOwner"`
+						MirrorURL     string `json:"mirrorUrl"`
+						Parent        *struct {
+							URL string `json:"url"`
+						} `json:"parent"`
+						DefaultBranchRef *struct {
+							Target struct {
+								OID string `json:"oid"`
+							} `json:"target"`
+						} `json:"defaultBranchRef"`
+						Languages struct {
+							Edges []struct {
+								Size int64 `json:"size"`
+								Node struct {
+									Name string `json:"name"`
+								} `json:"node"`
+							} `json:"edges"`
+						} `json:"languages"`
+					} `json:"nodes"`
+				} `json:"repositories"`
+			} `json:"user"`
+		}
+		if err := p.query(ctx, repoHeadsQuery, vars, &data); err != nil {
+			return heads, err
+		}
+		if data.User == nil {
+			return heads, fmt.Errorf("github: user %s not found", handle)
+		}
+
+		for _, n := range data.User.Repositories.Nodes {
+			head := githubRepoHead{
+				Source:    n.MirrorURL,
+				Languages: make(map[string]int64, len(n.Languages.Edges)),
+			}
+			if n.Parent != nil && head.Source == "" {
+				head.Source = n.Parent.URL
+			}
+			if n.DefaultBranchRef != nil {
+				head.SHA = n.DefaultBranchRef.Target.OID
+			}
+			for _, e := range n.Languages.Edges {
+				head.Languages[languages.Normalize(e.Node.Name)] += e.Size
+			}
+			heads[n.NameWithOwner] = head
+		}
+
+		page := data.User.Repositories.PageInfo
+		if !page.HasNextPage {
+			return heads, nil
+		}
+		vars["cursor"] = page.EndCursor
+	}
+}
+
+func (p *Provider) query(ctx context.Context, query string, vars map[string]any, out any) error {
+	buf, err := json.Marshal(map[string]any{
+		"query":     query,
+		"variables": vars,
+	})
+	if err != nil {
+		return fmt.Errorf("github: marshal graphql body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.graphqlURL, bytes.NewReader(buf))
+	if err != nil {
+		return fmt.Errorf("github: new graphql request: %w", err)
+	}
+	p.applyHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("github: do graphql request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("github: graphql unexpected status %d body=%s", resp.StatusCode, string(body))
+	}
+
+	var r struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return fmt.Errorf("github: decode graphql response: %w", err)
+	}
+	if len(r.Errors) > 0 {
+		return fmt.Errorf("github: graphql error: %s", r.Errors[0].Message)
+	}
+
+	if err := json.Unmarshal(r.Data, out); err != nil {
+		return fmt.Errorf("github: decode graphql data: %w", err)
+	}
+
+	return nil
+}
+
 func (p *Provider) fetchAuthenticatedUser(ctx context.Context) (*githubUser, error) {
 	endpoint := fmt.Sprintf("%s/user", p.baseURL)
 
@@ -507,34 +714,104 @@ func (p *Provider) fetchPrivateRepos(ctx context.Context) ([]githubRepo, error)
 	return all, nil
 }
 
-func fetchAvatar(ctx context.Context, client *http.Client, avatarURL string) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, avatarURL, nil)
+func headLanguages(repos []githubRepo, heads map[string]githubRepoHead) (map[string]map[string]int64, []githubRepo) {
+	results := make(map[string]map[string]int64, len(repos))
+	var missing []githubRepo
+	for _, r := range repos {
+		head, ok := heads[r.FullName]
+		if !ok || head.Languages == nil {
+			missing = append(missing, r)
+			continue
+		}
+		results[r.FullName] = head.Languages
+	}
+	return results, missing
+}
+
+func (p *Provider) fetchAllRepoLanguages(ctx context.Context, repos []githubRepo) (map[string]map[string]int64, int) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		results   = make(map[string]map[string]int64, len(repos))
+		succeeded int
+	)
+
+	jobs := make(chan githubRepo)
+
+	for range min(languageConcurrency, len(repos)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range jobs {
+				langs, err := p.fetchRepoLanguages(ctx, r.FullName)
+
+				mu.Lock()
+				if err != nil {
+					if ctx.Err() == nil {
+						log.Printf("github: fetch languages failed for %s: %v", r.FullName, err)
+					}
+					var rl *httpclient.RateLimitError
+					if errors.As(err, &rl) {
+						cancel()
+					}
+				} else {
+					counts := make(map[string]int64, len(langs))
+					for name, n := range langs {
+						counts[languages.Normalize(name)] += n
+					}
+					results[r.FullName] = counts
+					succeeded++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, r := range repos {
+		select {
+		case jobs <- r:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, len(repos) - succeeded
+}
+
+func (p *Provider) fetchRepoLanguages(ctx context.Context, fullName string) (map[string]int64, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/languages", p.baseURL, fullName)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
-		return "", fmt.Errorf("new avatar request: %w", err)
+		return nil, fmt.Errorf("new request: %w", err)
 	}
+	p.applyHeaders(req)
 
-	resp, err := client.Do(req)
+	resp, err := p.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("fetch avatar: %w", err)
+		return nil, fmt.Errorf("do request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("avatar fetch failed with status %d", resp.StatusCode)
+	if resp.StatusCode == http.StatusNotFound {
+		return map[string]int64{}, nil
 	}
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("read avatar body: %w", err)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
 	}
 
-	contentType := resp.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = http.DetectContentType(data)
+	var langs map[string]int64
+	if err := json.NewDecoder(resp.Body).Decode(&langs); err != nil {
+		return nil, fmt.Errorf("decode languages response: %w", err)
 	}
 
-	encoded := base64.StdEncoding.EncodeToString(data)
-	return fmt.Sprintf("data:%s;base64,%s", contentType, encoded), nil
+	return langs, nil
 }
 
 func (p *Provider) applyHeaders(req *http.Request) {
@@ -545,6 +822,48 @@ func (p *Provider) applyHeaders(req *http.Request) {
 	}
 }
 
+func (p *Provider) filterRepos(repos []githubRepo) (kept, skipped []githubRepo) {
+	for _, r := range repos {
+		meta := core.RepoMeta{
+			Name:     r.Name,
+			FullName: r.FullName,
+			Fork:     r.Fork,
+			Archived: r.Archived,
+			Mirror:   r.MirrorURL != "",
+			Template: r.IsTemplate,
+			Topics:   r.Topics,
+		}
+		if p.filter.Keep(meta) {
+			kept = append(kept, r)
+		} else {
+			skipped = append(skipped, r)
+		}
+	}
+	return kept, skipped
+}
+
+func (p *Provider) repositories(repos []githubRepo, repoLanguages map[string]map[string]int64, heads map[string]githubRepoHead) []core.Repository {
+	out := make([]core.Repository, 0, len(repos))
+	for _, r := range repos {
+		head := heads[r.FullName]
+		if head.Source == "" {
+			head.Source = r.MirrorURL
+		}
+
+		out = append(out, core.Repository{
+			Provider:  p.label,
+			Name:      r.FullName,
+			URL:       r.HTMLURL,
+			Private:   r.Private,
+			Stars:     r.StargazersCount,
+			Source:    head.Source,
+			HeadSHA:   head.SHA,
+			Languages: computeLanguages(repoLanguages[r.FullName]),
+		})
+	}
+	return out
+}
+
 func sumStars(repos []githubRepo) int {
 	var total int
 	for _, r := range repos {
@@ -563,69 +882,16 @@ func countPrivate(repos []githubRepo) int {
 	return n
 }
 
-func computeLanguages(repos []githubRepo) ([]core.LanguageStat, int) {
-	counts := make(map[string]int)
-	for _, r := range repos {
-		if r.Language == "" {
-			continue
-		}
-		counts[r.Language]++
-	}
-	if len(counts) == 0 {
-		return nil, 0
-	}
-
-	totalLanguages := len(counts)
-
-	var total int
-	for _, c := range counts {
-		total += c
-	}
-
+func computeLanguages(counts map[string]int64) []core.LanguageStat {
 	langs := make([]core.LanguageStat, 0, len(counts))
 	for name, c := range counts {
-		pct := float64(c) / float64(total) * 100.0
-
-		color, ok := languageColors[name]
-		if !ok {
-			color = "#586069"
-		}
-
 		langs = append(langs, core.LanguageStat{
-			Name:       name,
-			Percentage: pct,
-			Color:      color,
+			Name:  name,
+			Bytes: c,
+			Color: languages.Color(name),
 		})
 	}
-
-	sort.Slice(langs, func(i, j int) bool {
-		return langs[i].Percentage > langs[j].Percentage
-	})
-
-	if len(langs) <= 9 {
-		return langs, totalLanguages
-	}
-
-	top9 := langs[:9]
-	var othersTotal float64
-	for i := 9; i < len(langs); i++ {
-		othersTotal += langs[i].Percentage
-	}
-
-	result := append(top9, core.LanguageStat{
-		Name:       "Others",
-		Percentage: othersTotal,
-		Color:      "#586069",
-	})
-
-	return result, totalLanguages
-}
-
-func pickName(u *githubUser) string {
-	if u.Name != "" {
-		return u.Name
-	}
-	return u.Login
+	return langs
 }
 
 func extractNextLink(linkHeader string) string {
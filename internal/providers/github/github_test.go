@@ -0,0 +1,144 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vukan322/devmetrics/internal/core"
+)
+
+func githubServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var (
+		mu          sync.Mutex
+		restLookups []string
+	)
+
+	mux := http.NewServeMux()
+	reply := func(w http.ResponseWriter, body string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}
+
+	mux.HandleFunc("GET /users/ada", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, `{"login": "ada", "public_repos": 3}`)
+	})
+	mux.HandleFunc("GET /users/ada/repos", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, `[{"name": "engine", "full_name": "ada/engine"}, {"name": "site", "full_name": "ada/site"}, {"name": "broken", "full_name": "ada/broken"}]`)
+	})
+	mux.HandleFunc("GET /user", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, `{"login": "ada"}`)
+	})
+	mux.HandleFunc("GET /user/repos", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, `[]`)
+	})
+	mux.HandleFunc("GET /search/issues", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, `{"total_count": 0}`)
+	})
+	mux.HandleFunc("GET /repos/{owner}/{repo}/languages", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("owner") + "/" + r.PathValue("repo")
+		mu.Lock()
+		restLookups = append(restLookups, name)
+		mu.Unlock()
+
+		switch name {
+		case "ada/engine":
+			reply(w, `{"Go": 3000}`)
+		case "ada/site":
+			reply(w, `{"HTML": 500, "JavaScript": 200}`)
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+	})
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if strings.Contains(body.Query, "contributionsCollection") {
+			reply(w, `{"data": {"user": {"contributionsCollection": {"totalCommitContributions": 0, "contributionCalendar": {"weeks": []}}}}}`)
+			return
+		}
+		reply(w, `{"data": {"user": {"repositories": {
+			"pageInfo": {"hasNextPage": false},
+			"nodes": [
+				{"nameWithOwner": "ada/engine", "languages": {"edges": [{"size": 3000, "node": {"name": "golang"}}]}},
+				{"nameWithOwner": "ada/site", "languages": {"edges": [{"size": 500, "node": {"name": "HTML"}}, {"size": 200, "node": {"name": "JavaScript"}}]}}
+			]
+		}}}}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		out := append([]string(nil), restLookups...)
+		sort.Strings(out)
+		return out
+	}
+}
+
+func TestFetchLanguageBytes(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       string
+		wantLookups []string
+	}{
+		{name: "token reads languages from graphql", token: "secret", wantLookups: []string{"ada/broken"}},
+		{name: "no token falls back to rest", wantLookups: []string{"ada/broken", "ada/engine", "ada/site"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, lookups := githubServer(t)
+
+			p := New(tt.token)
+			p.client = srv.Client()
+			p.baseURL = srv.URL
+			p.graphqlURL = srv.URL + "/graphql"
+
+			stats, err := p.Fetch(context.Background(), "ada")
+			if err != nil {
+				t.Fatalf("Fetch: %v", err)
+			}
+
+			got := make(map[string]int64)
+			for _, l := range stats.Activity.TopLanguages {
+				got[l.Name] = l.Bytes
+			}
+			want := map[string]int64{"Go": 3000, "HTML": 500, "JavaScript": 200}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("language bytes = %v, want %v", got, want)
+			}
+
+			if got := lookups(); !reflect.DeepEqual(got, tt.wantLookups) {
+				t.Errorf("REST language lookups = %v, want %v", got, tt.wantLookups)
+			}
+
+			var reports []core.FieldReport
+			for _, r := range stats.Diagnostics.Reports {
+				if r.Field == core.FieldLanguages {
+					reports = append(reports, r)
+				}
+			}
+			wantReports := []core.FieldReport{
+				{Source: "github", Field: core.FieldLanguages, Status: core.StatusSupplied},
+				{Source: "github", Field: core.FieldLanguages, Status: core.StatusFailed, Error: "1 of 3 requests failed"},
+			}
+			if !reflect.DeepEqual(reports, wantReports) {
+				t.Errorf("language reports = %+v, want %+v", reports, wantReports)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package gitlab
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/vukan322/devmetrics/internal/providers"
+)
+
+func Factory() providers.Factory {
+	return providers.Factory{
+		Name:     "gitlab",
+		Title:    "GitLab",
+		Prefix:   "DEV_METRICS_GITLAB",
+		Multiple: true,
+		Keys: []providers.Key{
+			{Name: "USER", Required: true, Usage: "GitLab username"},
+			{Name: "TOKEN", Usage: "personal access token (read_api); needed for issues and merge requests"},
+			{Name: "URL", Default: defaultBaseURL, Usage: "instance URL for self-managed GitLab"},
+			{Name: "LANGUAGE_CONCURRENCY", Default: strconv.Itoa(defaultLanguageConcurrency), Usage: "parallel per-project language lookups"},
+		},
+		Validate: func(cfg providers.Config) error {
+			n, err := strconv.Atoi(cfg.Get("LANGUAGE_CONCURRENCY"))
+			if err != nil || n <= 0 {
+				return fmt.Errorf("LANGUAGE_CONCURRENCY must be a positive integer, got %q", cfg.Get("LANGUAGE_CONCURRENCY"))
+			}
+			return nil
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			concurrency, _ := strconv.Atoi(cfg.Get("LANGUAGE_CONCURRENCY"))
+			p := NewInstance(cfg.Get("URL"), cfg.Get("TOKEN"), cfg.Get("USER")).WithLanguageConcurrency(concurrency)
+			return p, cfg.Get("USER"), nil
+		},
+	}
+}
@@ -3,62 +3,159 @@ package gitlab
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/vukan322/devmetrics/internal/core"
+	"github.com/vukan322/devmetrics/internal/httpclient"
+	"github.com/vukan322/devmetrics/internal/languages"
+)
+
+const (
+	defaultBaseURL             = "https://gitlab.com"
+	defaultLanguageConcurrency = 8
+	defaultRepositorySize      = 1 << 20
 )
 
 type Provider struct {
-	client  *http.Client
-	baseURL string
-	token   string
-	user    string
+	client              *http.Client
+	baseURL             string
+	token               string
+	user                string
+	label               string
+	languageConcurrency int
+	filter              core.RepoFilter
 }
 
 func New(token, user string) *Provider {
+	return NewInstance(defaultBaseURL, token, user)
+}
+
+func NewInstance(baseURL, token, user string) *Provider {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
 	return &Provider{
-		client:  &http.Client{Timeout: 10 * time.Second},
-		baseURL: "https://gitlab.com/api/v4",
+		client:  httpclient.New(),
+		baseURL: httpclient.APIURL(baseURL, "/api/v4"),
 		token:   token,
 		user:    user,
+		label:   httpclient.HostLabel(baseURL, "gitlab", map[string]string{"gitlab.com": "gitlab"}),
+
+		languageConcurrency: defaultLanguageConcurrency,
 	}
 }
 
+func (p *Provider) WithLanguageConcurrency(n int) *Provider {
+	if n > 0 {
+		p.languageConcurrency = n
+	}
+	return p
+}
+
 func (p *Provider) Name() string {
 	return "gitlab"
 }
 
+func (p *Provider) SetRepoFilter(f core.RepoFilter) {
+	p.filter = f
+}
+
 type gitlabUser struct {
-	ID       int    `json:"id"`
-	Username string `json:"username"`
-	Name     string `json:"name"`
-	Avatar   string `json:"avatar_url"`
+	ID        int       `json:"id"`
+	Username  string    `json:"username"`
+	Name      string    `json:"name"`
+	Avatar    string    `json:"avatar_url"`
+	Followers int       `json:"followers"`
+	Following int       `json:"following"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type gitlabProject struct {
-	ID                int    `json:"id"`
-	Name              string `json:"name"`
-	PathWithNamespace string `json:"path_with_namespace"`
-	Visibility        string `json:"visibility"`
-	StarCount         int    `json:"star_count"`
+	ID                int      `json:"id"`
+	Name              string   `json:"name"`
+	PathWithNamespace string   `json:"path_with_namespace"`
+	Visibility        string   `json:"visibility"`
+	StarCount         int      `json:"star_count"`
+	WebURL            string   `json:"web_url"`
+	DefaultBranch     string   `json:"default_branch"`
+	Archived          bool     `json:"archived"`
+	Mirror            bool     `json:"mirror"`
+	ImportURL         string   `json:"import_url"`
+	Topics            []string `json:"topics"`
+	ForkedFromProject *struct {
+		ID     int    `json:"id"`
+		WebURL string `json:"web_url"`
+	} `json:"forked_from_project"`
+	Statistics *struct {
+		RepositorySize int64 `json:"repository_size"`
+	} `json:"statistics"`
 }
 
 type gitlabLanguages map[string]float64
 
+type gitlabProjectDetails struct {
+	Languages gitlabLanguages
+	HeadSHA   string
+}
+
+type gitlabMergeRequest struct {
+	ID        int `json:"id"`
+	ProjectID int `json:"project_id"`
+}
+
+type gitlabIssueStatistics struct {
+	Statistics struct {
+		Counts struct {
+			All    int `json:"all"`
+			Closed int `json:"closed"`
+			Opened int `json:"opened"`
+		} `json:"counts"`
+	} `json:"statistics"`
+}
+
+type gitlabEvent struct {
+	ActionName string    `json:"action_name"`
+	CreatedAt  time.Time `json:"created_at"`
+	PushData   *struct {
+		CommitCount int `json:"commit_count"`
+	} `json:"push_data"`
+}
+
 func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
 	user, err := p.fetchUser(ctx, handle)
 	if err != nil {
 		return core.DevStats{}, fmt.Errorf("gitlab: fetch user: %w", err)
 	}
 
-	projects, err := p.fetchProjects(ctx, user.ID)
+	var diag core.Diagnostics
+
+	details, err := p.fetchUserDetails(ctx, user.ID)
+	if err != nil {
+		log.Printf("gitlab: fetchUserDetails error for %s: %v", handle, err)
+	} else {
+		user = details
+	}
+	diag.Record(p.label, core.FieldFollowers, err)
+	diag.Record(p.label, core.FieldJoined, err)
+
+	owned, err := p.fetchProjects(ctx, user.ID)
 	if err != nil {
 		return core.DevStats{}, fmt.Errorf("gitlab: fetch projects: %w", err)
 	}
+	projects, skipped := p.filterProjects(owned)
+	diag.Supplied(p.label, core.FieldRepos, core.FieldStars)
+	diag.Unsupported(p.label, core.FieldReviews, core.FieldPackages)
 
 	publicCount := 0
 	privateCount := 0
@@ -74,27 +171,105 @@ func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, err
 		totalStars += pr.StarCount
 	}
 
-	topLangs, _ := p.computeLanguages(ctx, projects)
+	projectDetails, failedLanguages := p.fetchAllProjectDetails(ctx, projects)
+	projectLanguages := languageBytes(projects, projectDetails)
+
+	counts := make(map[string]int64)
+	for _, projectCounts := range projectLanguages {
+		for name, n := range projectCounts {
+			counts[name] += n
+		}
+	}
+	langs := computeLanguages(counts)
+
+	if failedLanguages == 0 || len(langs) > 0 {
+		diag.Supplied(p.label, core.FieldLanguages)
+	}
+	if failedLanguages > 0 {
+		err := fmt.Errorf("languages unavailable for %d of %d projects", failedLanguages, len(projects))
+		log.Printf("gitlab: fetch languages error for %s: %v", handle, err)
+		diag.Failed(p.label, core.FieldLanguages, err)
+	}
+
+	contribs, totalCommits, err := p.fetchContributions(ctx, user.ID)
+	if err != nil {
+		log.Printf("gitlab: fetchContributions error for %s: %v", handle, err)
+		contribs = make(map[time.Time]int)
+		totalCommits = 0
+	}
+	diag.Record(p.label, core.FieldContributions, err)
+
+	currentStreak, longestStreak := core.ComputeStreaks(contribs)
+
+	var (
+		issueStats       core.IssueStats
+		prStats          core.PRStats
+		contributedCount int
+	)
+
+	if p.token == "" {
+		diag.Unsupported(p.label, core.FieldIssues, core.FieldPullRequests, core.FieldContributedRepos)
+	} else {
+		issueStats, err = p.fetchIssueStats(ctx, user.ID)
+		if err != nil {
+			log.Printf("gitlab: fetchIssueStats error for %s: %v", handle, err)
+			issueStats = core.IssueStats{}
+		}
+		diag.Record(p.label, core.FieldIssues, err)
+
+		prStats, err = p.fetchMRStats(ctx, user.ID)
+		if err != nil {
+			log.Printf("gitlab: fetchMRStats error for %s: %v", handle, err)
+			prStats = core.PRStats{}
+		}
+		diag.Record(p.label, core.FieldPullRequests, err)
+
+		contributedCount, err = p.fetchContributedProjects(ctx, user.ID, owned)
+		if err != nil {
+			log.Printf("gitlab: fetchContributedProjects error for %s: %v", handle, err)
+			contributedCount = 0
+		}
+		diag.Record(p.label, core.FieldContributedRepos, err)
+	}
+
+	avatarData, err := httpclient.FetchAvatar(ctx, p.client, user.Avatar)
+	if err != nil {
+		avatarData = ""
+	}
 
 	identity := core.Identity{
-		Name:     pickName(user),
+		Name:     core.DisplayName(user.Name, user.Username),
 		Username: user.Username,
-		Avatar:   "",
-		Handles:  []string{"gitlab: " + handle},
+		Avatar:   avatarData,
+		Handles:  []string{p.label + ": " + handle},
 	}
 
 	totals := core.Totals{
-		PublicRepos:  publicCount,
-		PrivateRepos: privateCount,
-		Stars:        totalStars,
+		PublicRepos:      publicCount,
+		PrivateRepos:     privateCount,
+		Stars:            totalStars,
+		Followers:        user.Followers,
+		Following:        user.Following,
+		ContributedRepos: contributedCount,
+		Joined:           user.CreatedAt,
+		Commits:          totalCommits,
+		CurrentStreak:    currentStreak,
+		LongestStreak:    longestStreak,
+		CommitsThisWeek:  core.CommitsThisWeek(contribs),
+		FilteredRepos:    len(skipped),
 	}
 
 	stats := core.DevStats{
 		Identity: identity,
 		Totals:   totals,
 		Activity: core.Activity{
-			TopLanguages: topLangs,
+			ContributionsPerDay: contribs,
+			TopLanguages:        langs,
+			Issues:              issueStats,
+			PullRequests:        prStats,
 		},
+		Repositories: p.repositories(projects, projectLanguages, projectDetails),
+		Diagnostics:  diag,
 	}
 
 	return stats, nil
@@ -131,16 +306,49 @@ func (p *Provider) fetchUser(ctx context.Context, handle string) (*gitlabUser, e
 	return &users[0], nil
 }
 
+func (p *Provider) fetchUserDetails(ctx context.Context, userID int) (*gitlabUser, error) {
+	endpoint := fmt.Sprintf("%s/users/%d", p.baseURL, userID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gitlab: new user details request: %w", err)
+	}
+	p.applyAuth(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gitlab: do user details request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("gitlab: fetch user details: unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	var u gitlabUser
+	if err := json.NewDecoder(resp.Body).Decode(&u); err != nil {
+		return nil, fmt.Errorf("gitlab: decode user details response: %w", err)
+	}
+
+	return &u, nil
+}
+
 func (p *Provider) fetchProjects(ctx context.Context, userID int) ([]gitlabProject, error) {
 	var all []gitlabProject
 	page := 1
 
+	statistics := ""
+	if p.token != "" {
+		statistics = "&statistics=true"
+	}
+
 	for {
 		endpoint := fmt.Sprintf(
-			"%s/users/%d/projects?per_page=100&page=%d&simple=true&order_by=last_activity_at&sort=desc",
+			"%s/users/%d/projects?per_page=100&page=%d&order_by=last_activity_at&sort=desc%s",
 			p.baseURL,
 			userID,
 			page,
+			statistics,
 		)
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
@@ -175,64 +383,370 @@ func (p *Provider) fetchProjects(ctx context.Context, userID int) ([]gitlabProje
 	return all, nil
 }
 
-func (p *Provider) computeLanguages(ctx context.Context, projects []gitlabProject) ([]core.LanguageStat, int) {
-	counts := map[string]float64{}
+func (p *Provider) fetchIssueStats(ctx context.Context, userID int) (core.IssueStats, error) {
+	endpoint := fmt.Sprintf("%s/issues_statistics?author_id=%d&scope=all", p.baseURL, userID)
 
-	for _, pr := range projects {
-		langs, err := p.fetchProjectLanguages(ctx, pr.ID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return core.IssueStats{}, fmt.Errorf("gitlab: new issues statistics request: %w", err)
+	}
+	p.applyAuth(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return core.IssueStats{}, fmt.Errorf("gitlab: do issues statistics request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return core.IssueStats{}, fmt.Errorf("gitlab: fetch issues statistics: unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	var stats gitlabIssueStatistics
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return core.IssueStats{}, fmt.Errorf("gitlab: decode issues statistics response: %w", err)
+	}
+
+	return core.IssueStats{
+		Open:   stats.Statistics.Counts.Opened,
+		Closed: stats.Statistics.Counts.Closed,
+	}, nil
+}
+
+func (p *Provider) fetchMRStats(ctx context.Context, userID int) (core.PRStats, error) {
+	open, err := p.countMergeRequests(ctx, userID, "opened")
+	if err != nil {
+		return core.PRStats{}, fmt.Errorf("count opened merge requests: %w", err)
+	}
+
+	merged, err := p.countMergeRequests(ctx, userID, "merged")
+	if err != nil {
+		return core.PRStats{}, fmt.Errorf("count merged merge requests: %w", err)
+	}
+
+	closed, err := p.countMergeRequests(ctx, userID, "closed")
+	if err != nil {
+		return core.PRStats{}, fmt.Errorf("count closed merge requests: %w", err)
+	}
+
+	return core.PRStats{
+		Open:   open,
+		Merged: merged,
+		Closed: closed,
+	}, nil
+}
+
+func (p *Provider) countMergeRequests(ctx context.Context, userID int, state string) (int, error) {
+	endpoint := fmt.Sprintf(
+		"%s/merge_requests?author_id=%d&scope=all&state=%s&per_page=1",
+		p.baseURL,
+		userID,
+		url.QueryEscape(state),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, fmt.Errorf("gitlab: new merge requests request: %w", err)
+	}
+	p.applyAuth(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("gitlab: do merge requests request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("gitlab: fetch merge requests: unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	total := resp.Header.Get("X-Total")
+	if total == "" {
+		return 0, fmt.Errorf("gitlab: merge requests response from %s has no X-Total header", endpoint)
+	}
+
+	n, err := strconv.Atoi(total)
+	if err != nil {
+		return 0, fmt.Errorf("gitlab: parse X-Total %q: %w", total, err)
+	}
+
+	return n, nil
+}
+
+func (p *Provider) fetchContributedProjects(ctx context.Context, userID int, owned []gitlabProject) (int, error) {
+	ownedIDs := make(map[int]bool, len(owned))
+	for _, pr := range owned {
+		ownedIDs[pr.ID] = true
+	}
+
+	contributed := make(map[int]bool)
+	page := 1
+
+	for {
+		endpoint := fmt.Sprintf(
+			"%s/merge_requests?author_id=%d&scope=all&state=merged&per_page=100&page=%d",
+			p.baseURL,
+			userID,
+			page,
+		)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 		if err != nil {
-			log.Printf("gitlab: fetch languages failed for project %d (%s): %v", pr.ID, pr.PathWithNamespace, err)
-			continue
+			return 0, fmt.Errorf("gitlab: new merged merge requests request: %w", err)
+		}
+		p.applyAuth(req)
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return 0, fmt.Errorf("gitlab: do merged merge requests request: %w", err)
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			resp.Body.Close()
+			return 0, fmt.Errorf("gitlab: fetch merged merge requests: unexpected status %d from %s", resp.StatusCode, endpoint)
+		}
+
+		var mrs []gitlabMergeRequest
+		if err := json.NewDecoder(resp.Body).Decode(&mrs); err != nil {
+			resp.Body.Close()
+			return 0, fmt.Errorf("gitlab: decode merged merge requests response: %w", err)
 		}
+		resp.Body.Close()
 
-		for name, val := range langs {
-			counts[name] += val
+		if len(mrs) == 0 {
+			break
 		}
+
+		for _, mr := range mrs {
+			if !ownedIDs[mr.ProjectID] {
+				contributed[mr.ProjectID] = true
+			}
+		}
+
+		page++
 	}
 
-	if len(counts) == 0 {
-		return nil, 0
+	return len(contributed), nil
+}
+
+func (p *Provider) fetchContributions(ctx context.Context, userID int) (map[time.Time]int, int, error) {
+	since := time.Now().UTC().AddDate(-1, 0, -1)
+	contribs := make(map[time.Time]int)
+	totalCommits := 0
+	page := 1
+
+	for {
+		endpoint := fmt.Sprintf(
+			"%s/users/%d/events?after=%s&per_page=100&page=%d",
+			p.baseURL,
+			userID,
+			since.Format("2006-01-02"),
+			page,
+		)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, 0, fmt.Errorf("gitlab: new events request: %w", err)
+		}
+		p.applyAuth(req)
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return nil, 0, fmt.Errorf("gitlab: do events request: %w", err)
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			resp.Body.Close()
+			return nil, 0, fmt.Errorf("gitlab: fetch events: unexpected status %d from %s", resp.StatusCode, endpoint)
+		}
+
+		var events []gitlabEvent
+		if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+			resp.Body.Close()
+			return nil, 0, fmt.Errorf("gitlab: decode events response: %w", err)
+		}
+		resp.Body.Close()
+
+		if len(events) == 0 {
+			break
+		}
+
+		for _, e := range events {
+			t := e.CreatedAt.UTC()
+			day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+			contribs[day]++
+
+			if e.PushData != nil {
+				totalCommits += e.PushData.CommitCount
+			}
+		}
+
+		page++
 	}
 
-	totalLanguages := len(counts)
-	var total float64
-	for _, v := range counts {
-		total += v
+	return contribs, totalCommits, nil
+}
+
+func languageBytes(projects []gitlabProject, details map[int]gitlabProjectDetails) map[int]map[string]int64 {
+	out := make(map[int]map[string]int64, len(details))
+
+	fallback := medianRepositorySize(projects)
+	for _, pr := range projects {
+		d, ok := details[pr.ID]
+		if !ok {
+			continue
+		}
+
+		size := fallback
+		if pr.Statistics != nil && pr.Statistics.RepositorySize > 0 {
+			size = pr.Statistics.RepositorySize
+		}
+
+		counts := make(map[string]int64, len(d.Languages))
+		for name, pct := range d.Languages {
+			counts[languages.Normalize(name)] += int64(pct / 100.0 * float64(size))
+		}
+		out[pr.ID] = counts
 	}
 
+	return out
+}
+
+func computeLanguages(counts map[string]int64) []core.LanguageStat {
 	langStats := make([]core.LanguageStat, 0, len(counts))
 	for name, v := range counts {
-		pct := (v / total) * 100.0
 		langStats = append(langStats, core.LanguageStat{
-			Name:       name,
-			Percentage: pct,
+			Name:  name,
+			Bytes: v,
+			Color: languages.Color(name),
 		})
 	}
+	return langStats
+}
 
-	for i := 0; i < len(langStats); i++ {
-		for j := i + 1; j < len(langStats); j++ {
-			if langStats[j].Percentage > langStats[i].Percentage {
-				langStats[i], langStats[j] = langStats[j], langStats[i]
-			}
+func (p *Provider) repositories(projects []gitlabProject, projectLanguages map[int]map[string]int64, details map[int]gitlabProjectDetails) []core.Repository {
+	out := make([]core.Repository, 0, len(projects))
+	for _, pr := range projects {
+		source := ""
+		switch {
+		case pr.Mirror && pr.ImportURL != "":
+			source = pr.ImportURL
+		case pr.ForkedFromProject != nil:
+			source = pr.ForkedFromProject.WebURL
 		}
+
+		out = append(out, core.Repository{
+			Provider:  p.label,
+			Name:      pr.PathWithNamespace,
+			URL:       pr.WebURL,
+			Private:   pr.Visibility != "public",
+			Stars:     pr.StarCount,
+			Source:    source,
+			HeadSHA:   details[pr.ID].HeadSHA,
+			Languages: computeLanguages(projectLanguages[pr.ID]),
+		})
 	}
+	return out
+}
 
-	if len(langStats) <= 9 {
-		return langStats, totalLanguages
+func (p *Provider) filterProjects(projects []gitlabProject) (kept, skipped []gitlabProject) {
+	for _, pr := range projects {
+		meta := core.RepoMeta{
+			Name:     pr.Name,
+			FullName: pr.PathWithNamespace,
+			Fork:     pr.ForkedFromProject != nil,
+			Archived: pr.Archived,
+			Mirror:   pr.Mirror,
+			Topics:   pr.Topics,
+		}
+		if p.filter.Keep(meta) {
+			kept = append(kept, pr)
+		} else {
+			skipped = append(skipped, pr)
+		}
 	}
+	return kept, skipped
+}
+
+func medianRepositorySize(projects []gitlabProject) int64 {
+	var sizes []int64
+	for _, pr := range projects {
+		if pr.Statistics != nil && pr.Statistics.RepositorySize > 0 {
+			sizes = append(sizes, pr.Statistics.RepositorySize)
+		}
+	}
+	if len(sizes) == 0 {
+		return defaultRepositorySize
+	}
+
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] < sizes[j] })
+	return sizes[len(sizes)/2]
+}
 
-	top9 := langStats[:9]
-	var othersTotal float64
-	for i := 9; i < len(langStats); i++ {
-		othersTotal += langStats[i].Percentage
+func (p *Provider) fetchAllProjectDetails(ctx context.Context, projects []gitlabProject) (map[int]gitlabProjectDetails, int) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := p.languageConcurrency
+	if workers <= 0 {
+		workers = defaultLanguageConcurrency
+	}
+	workers = min(workers, len(projects))
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		results   = make(map[int]gitlabProjectDetails, len(projects))
+		succeeded int
+	)
+
+	jobs := make(chan gitlabProject)
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pr := range jobs {
+				langs, err := p.fetchProjectLanguages(ctx, pr.ID)
+
+				var head string
+				if err == nil && p.token != "" && pr.DefaultBranch != "" {
+					var headErr error
+					if head, headErr = p.fetchProjectHead(ctx, pr); headErr != nil && ctx.Err() == nil {
+						log.Printf("gitlab: fetch head commit failed for project %d (%s): %v", pr.ID, pr.PathWithNamespace, headErr)
+					}
+				}
+
+				mu.Lock()
+				if err != nil {
+					if ctx.Err() == nil {
+						log.Printf("gitlab: fetch languages failed for project %d (%s): %v", pr.ID, pr.PathWithNamespace, err)
+					}
+					var rl *httpclient.RateLimitError
+					if errors.As(err, &rl) {
+						cancel()
+					}
+				} else {
+					results[pr.ID] = gitlabProjectDetails{Languages: langs, HeadSHA: head}
+					succeeded++
+				}
+				mu.Unlock()
+			}
+		}()
 	}
 
-	result := append(top9, core.LanguageStat{
-		Name:       "Others",
-		Percentage: othersTotal,
-	})
+dispatch:
+	for _, pr := range projects {
+		select {
+		case jobs <- pr:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
 
-	return result, totalLanguages
+	return results, len(projects) - succeeded
 }
 
 func (p *Provider) fetchProjectLanguages(ctx context.Context, projectID int) (gitlabLanguages, error) {
@@ -266,6 +780,40 @@ func (p *Provider) fetchProjectLanguages(ctx context.Context, projectID int) (gi
 	return langs, nil
 }
 
+func (p *Provider) fetchProjectHead(ctx context.Context, pr gitlabProject) (string, error) {
+	endpoint := fmt.Sprintf("%s/projects/%d/repository/branches/%s", p.baseURL, pr.ID, url.PathEscape(pr.DefaultBranch))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("gitlab: new branch request: %w", err)
+	}
+	p.applyAuth(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("gitlab: do branch request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("gitlab: fetch branch: unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	var branch struct {
+		Commit struct {
+			ID string `json:"id"`
+		} `json:"commit"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&branch); err != nil {
+		return "", fmt.Errorf("gitlab: decode branch response: %w", err)
+	}
+
+	return branch.Commit.ID, nil
+}
+
 func (p *Provider) applyAuth(req *http.Request) {
 	if p.token == "" {
 		return
@@ -273,10 +821,3 @@ func (p *Provider) applyAuth(req *http.Request) {
 	req.Header.Set("PRIVATE-TOKEN", p.token)
 	req.Header.Set("Accept", "application/json")
 }
-
-func pickName(u *gitlabUser) string {
-	if u.Name != "" {
-		return u.Name
-	}
-	return u.Username
-}
@@ -0,0 +1,76 @@
+package local
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+var extensionLanguages = map[string]string{
+	".asm":    "Assembly",
+	".c":      "C",
+	".h":      "C",
+	".cc":     "C++",
+	".cpp":    "C++",
+	".cxx":    "C++",
+	".hh":     "C++",
+	".hpp":    "C++",
+	".cs":     "C#",
+	".clj":    "Clojure",
+	".css":    "CSS",
+	".dart":   "Dart",
+	".ex":     "Elixir",
+	".exs":    "Elixir",
+	".erl":    "Erlang",
+	".fs":     "F#",
+	".go":     "Go",
+	".groovy": "Groovy",
+	".hs":     "Haskell",
+	".html":   "HTML",
+	".htm":    "HTML",
+	".java":   "Java",
+	".js":     "JavaScript",
+	".cjs":    "JavaScript",
+	".mjs":    "JavaScript",
+	".jsx":    "JavaScript",
+	".jl":     "Julia",
+	".kt":     "Kotlin",
+	".kts":    "Kotlin",
+	".lua":    "Lua",
+	".m":      "Objective-C",
+	".ml":     "OCaml",
+	".php":    "PHP",
+	".pl":     "Perl",
+	".ps1":    "PowerShell",
+	".py":     "Python",
+	".r":      "R",
+	".rb":     "Ruby",
+	".rs":     "Rust",
+	".scala":  "Scala",
+	".scss":   "SCSS",
+	".sh":     "Shell",
+	".bash":   "Shell",
+	".zsh":    "Shell",
+	".sql":    "SQL",
+	".swift":  "Swift",
+	".tf":     "HCL",
+	".ts":     "TypeScript",
+	".tsx":    "TypeScript",
+	".vue":    "Vue",
+	".svelte": "Svelte",
+	".zig":    "Zig",
+}
+
+var filenameLanguages = map[string]string{
+	"Dockerfile": "Dockerfile",
+	"Makefile":   "Makefile",
+}
+
+func languageForFile(name string) (string, bool) {
+	base := filepath.Base(name)
+	if lang, ok := filenameLanguages[base]; ok {
+		return lang, true
+	}
+
+	lang, ok := extensionLanguages[strings.ToLower(filepath.Ext(base))]
+	return lang, ok
+}
@@ -0,0 +1,23 @@
+package local
+
+import (
+	"path/filepath"
+
+	"github.com/vukan322/devmetrics/internal/providers"
+)
+
+func Factory() providers.Factory {
+	return providers.Factory{
+		Name:   "local",
+		Title:  "local",
+		Prefix: "DEV_METRICS_LOCAL",
+		Keys: []providers.Key{
+			{Name: "PATHS", Required: true, Usage: "directories to scan, separated by the OS path list separator"},
+			{Name: "EMAILS", Usage: "comma-separated author emails whose commits count (defaults to git config user.email)"},
+			{Name: "USER", UserFallback: true, Usage: "display handle (defaults to -user)"},
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			return New(filepath.SplitList(cfg.Get("PATHS")), cfg.List("EMAILS")), cfg.Get("USER"), nil
+		},
+	}
+}
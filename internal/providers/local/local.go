@@ -0,0 +1,352 @@
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vukan322/devmetrics/internal/core"
+	"github.com/vukan322/devmetrics/internal/languages"
+)
+
+const maxScannedFileSize = 1 << 20
+
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	".venv":        true,
+	"target":       true,
+}
+
+type Provider struct {
+	roots  []string
+	emails map[string]bool
+	filter core.RepoFilter
+}
+
+func New(roots []string, emails []string) *Provider {
+	set := make(map[string]bool, len(emails))
+	for _, e := range emails {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e != "" {
+			set[e] = true
+		}
+	}
+
+	return &Provider{
+		roots:  roots,
+		emails: set,
+	}
+}
+
+func (p *Provider) Name() string {
+	return "local"
+}
+
+func (p *Provider) SetRepoFilter(f core.RepoFilter) {
+	p.filter = f
+}
+
+func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return core.DevStats{}, fmt.Errorf("local: git executable not found: %w", err)
+	}
+
+	repos, err := p.findRepos(ctx)
+	if err != nil {
+		return core.DevStats{}, fmt.Errorf("local: find repos: %w", err)
+	}
+	repos, skipped := p.filterRepos(repos)
+
+	contribs := make(map[time.Time]int)
+	sizes := make(map[string]core.LanguageStat)
+	repositories := make([]core.Repository, 0, len(repos))
+	totalCommits := 0
+	commitFailures := 0
+	sizeFailures := 0
+
+	for _, repo := range repos {
+		days, err := p.commitsPerDay(ctx, repo)
+		if err != nil {
+			log.Printf("local: commitsPerDay error for %s: %v", repo, err)
+			commitFailures++
+		} else {
+			for day, count := range days {
+				contribs[day] += count
+				totalCommits += count
+			}
+		}
+
+		repoSizes, err := countCode(ctx, repo)
+		repositories = append(repositories, repository(ctx, repo, repoSizes))
+		if err != nil {
+			log.Printf("local: countCode error for %s: %v", repo, err)
+			sizeFailures++
+			continue
+		}
+		for lang, n := range repoSizes {
+			total := sizes[lang]
+			total.Bytes += n.Bytes
+			total.Lines += n.Lines
+			sizes[lang] = total
+		}
+	}
+
+	langs := computeLanguages(sizes)
+	currentStreak, longestStreak := core.ComputeStreaks(contribs)
+
+	var diag core.Diagnostics
+	diag.Supplied("local", core.FieldRepos)
+	diag.RecordPartial("local", core.FieldContributions, commitFailures, len(repos))
+	diag.RecordPartial("local", core.FieldLanguages, sizeFailures, len(repos))
+	diag.Unsupported("local", core.FieldStars, core.FieldFollowers, core.FieldContributedRepos, core.FieldJoined, core.FieldIssues, core.FieldPullRequests, core.FieldReviews, core.FieldPackages)
+
+	stats := core.DevStats{
+		Identity: core.Identity{
+			Username: handle,
+			Handles:  []string{"local: " + handle},
+		},
+		Totals: core.Totals{
+			PrivateRepos:    len(repos),
+			Commits:         totalCommits,
+			CurrentStreak:   currentStreak,
+			LongestStreak:   longestStreak,
+			CommitsThisWeek: core.CommitsThisWeek(contribs),
+			FilteredRepos:   len(skipped),
+		},
+		Activity: core.Activity{
+			ContributionsPerDay: contribs,
+			TopLanguages:        langs,
+		},
+		Repositories: repositories,
+		Diagnostics:  diag,
+	}
+
+	return stats, nil
+}
+
+func (p *Provider) filterRepos(repos []string) (kept, skipped []string) {
+	for _, repo := range repos {
+		if p.filter.Keep(core.RepoMeta{Name: filepath.Base(repo), FullName: filepath.ToSlash(repo)}) {
+			kept = append(kept, repo)
+		} else {
+			skipped = append(skipped, repo)
+		}
+	}
+	return kept, skipped
+}
+
+func (p *Provider) findRepos(ctx context.Context) ([]string, error) {
+	var repos []string
+	seen := make(map[string]bool)
+
+	for _, root := range p.roots {
+		root = strings.TrimSpace(root)
+		if root == "" {
+			continue
+		}
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				log.Printf("local: skipping %s: %v", path, err)
+				if d != nil && d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			if skippedDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+
+			if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+				abs, err := filepath.Abs(path)
+				if err != nil {
+					abs = path
+				}
+				if !seen[abs] {
+					seen[abs] = true
+					repos = append(repos, abs)
+				}
+				return filepath.SkipDir
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("walk %s: %w", root, err)
+		}
+	}
+
+	sort.Strings(repos)
+	return repos, nil
+}
+
+func (p *Provider) commitsPerDay(ctx context.Context, repo string) (map[time.Time]int, error) {
+	out, err := runGit(ctx, repo,
+		"log",
+		"--all",
+		"--no-merges",
+		"--since=1.year",
+		"--date=short",
+		"--format=%ae%x09%ad",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	emails := p.emails
+	if len(emails) == 0 {
+		emails = configuredEmail(ctx, repo)
+	}
+
+	m := make(map[time.Time]int)
+	if len(emails) == 0 {
+		return m, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		email, date, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok {
+			continue
+		}
+		if !emails[strings.ToLower(email)] {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+		m[time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)]++
+	}
+
+	return m, scanner.Err()
+}
+
+func configuredEmail(ctx context.Context, repo string) map[string]bool {
+	out, err := runGit(ctx, repo, "config", "user.email")
+	if err != nil {
+		return nil
+	}
+	email := strings.ToLower(strings.TrimSpace(string(out)))
+	if email == "" {
+		return nil
+	}
+	return map[string]bool{email: true}
+}
+
+func countCode(ctx context.Context, repo string) (map[string]core.LanguageStat, error) {
+	out, err := runGit(ctx, repo, "ls-files", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]core.LanguageStat)
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name == "" {
+			continue
+		}
+		lang, ok := languageForFile(name)
+		if !ok {
+			continue
+		}
+
+		path := filepath.Join(repo, name)
+		info, err := os.Lstat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxScannedFileSize {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if bytes.IndexByte(data, 0) >= 0 {
+			continue
+		}
+
+		lang = languages.Normalize(lang)
+		n := counts[lang]
+		n.Bytes += int64(len(data))
+		n.Lines += countLines(data)
+		counts[lang] = n
+	}
+
+	return counts, nil
+}
+
+func countLines(data []byte) int64 {
+	if len(data) == 0 {
+		return 0
+	}
+	n := int64(bytes.Count(data, []byte{'\n'}))
+	if data[len(data)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+func repository(ctx context.Context, repo string, sizes map[string]core.LanguageStat) core.Repository {
+	r := core.Repository{
+		Provider:  "local",
+		Name:      filepath.Base(repo),
+		URL:       filepath.ToSlash(repo),
+		Private:   true,
+		Languages: computeLanguages(sizes),
+	}
+
+	if out, err := runGit(ctx, repo, "remote", "get-url", "origin"); err == nil {
+		r.Source = strings.TrimSpace(string(out))
+	}
+	if out, err := runGit(ctx, repo, "rev-parse", "--verify", "--quiet", "HEAD"); err == nil {
+		r.HeadSHA = strings.TrimSpace(string(out))
+	}
+
+	return r
+}
+
+func runGit(ctx context.Context, repo string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repo}, args...)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg != "" {
+			return nil, fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return out, nil
+}
+
+func computeLanguages(sizes map[string]core.LanguageStat) []core.LanguageStat {
+	langs := make([]core.LanguageStat, 0, len(sizes))
+	for name, n := range sizes {
+		langs = append(langs, core.LanguageStat{
+			Name:  name,
+			Bytes: n.Bytes,
+			Lines: n.Lines,
+			Color: languages.Color(name),
+		})
+	}
+	return langs
+}
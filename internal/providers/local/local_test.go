@@ -0,0 +1,118 @@
+package local
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vukan322/devmetrics/internal/core"
+)
+
+func gitRepo(t *testing.T, email string, authors ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	dir := t.TempDir()
+	git := func(env []string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	git(nil, "init", "-q")
+	if email != "" {
+		git(nil, "config", "user.email", email)
+	}
+	for i, author := range authors {
+		name := filepath.Join(dir, "file.txt")
+		if err := os.WriteFile(name, []byte{byte('a' + i)}, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		env := []string{
+			"GIT_AUTHOR_NAME=dev", "GIT_AUTHOR_EMAIL=" + author,
+			"GIT_COMMITTER_NAME=dev", "GIT_COMMITTER_EMAIL=" + author,
+		}
+		git(env, "add", "file.txt")
+		git(env, "commit", "-q", "-m", "change")
+	}
+	return dir
+}
+
+func TestFetchCountsOnlyOwnCommits(t *testing.T) {
+	authors := []string{"me@example.com", "other@example.com", "Me@Example.com", "other@example.com", "other@example.com"}
+
+	tests := []struct {
+		name        string
+		configEmail string
+		emails      []string
+		want        int
+	}{
+		{name: "listed emails", emails: []string{"me@example.com"}, want: 2},
+		{name: "listed emails override git config", configEmail: "me@example.com", emails: []string{"other@example.com"}, want: 3},
+		{name: "falls back to git config", configEmail: "me@example.com", want: 2},
+		{name: "no email counts nothing", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := gitRepo(t, tt.configEmail, authors...)
+
+			stats, err := New([]string{dir}, tt.emails).Fetch(context.Background(), "me")
+			if err != nil {
+				t.Fatalf("Fetch: %v", err)
+			}
+			if stats.Totals.Commits != tt.want {
+				t.Errorf("Commits = %d, want %d", stats.Totals.Commits, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchCountsLinesPerLanguage(t *testing.T) {
+	dir := gitRepo(t, "me@example.com")
+
+	files := map[string]string{
+		"main.go":      "package main\n\nfunc main() {}\n",
+		"util.go":      "package main",
+		"script.sh":    "echo hi\necho bye\n",
+		"empty.go":     "",
+		"notes.random": "ignored\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command("git", "-C", dir, "add", ".")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v: %s", err, out)
+	}
+
+	stats, err := New([]string{dir}, nil).Fetch(context.Background(), "me")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	got := make(map[string]core.LanguageStat)
+	for _, l := range stats.Activity.TopLanguages {
+		got[l.Name] = core.LanguageStat{Bytes: l.Bytes, Lines: l.Lines}
+	}
+	want := map[string]core.LanguageStat{
+		"Go":    {Bytes: 41, Lines: 4},
+		"Shell": {Bytes: 17, Lines: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("languages = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,144 @@
+package packages
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vukan322/devmetrics/internal/core"
+)
+
+const (
+	defaultCratesURL = "https://crates.io/api/v1"
+	cratesPageSize   = 100
+	cratesDateLayout = "2006-01-02"
+)
+
+type CratesProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewCrates(baseURL string) *CratesProvider {
+	if baseURL == "" {
+		baseURL = defaultCratesURL
+	}
+
+	return &CratesProvider{
+		client:  newClient(),
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (p *CratesProvider) Name() string {
+	return "crates"
+}
+
+type cratesUserResponse struct {
+	User struct {
+		ID    int    `json:"id"`
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+type cratesListResponse struct {
+	Crates []struct {
+		Name string `json:"name"`
+	} `json:"crates"`
+	Meta struct {
+		Total int `json:"total"`
+	} `json:"meta"`
+}
+
+type cratesDownload struct {
+	Date      string `json:"date"`
+	Downloads int64  `json:"downloads"`
+}
+
+type cratesDownloadsResponse struct {
+	VersionDownloads []cratesDownload `json:"version_downloads"`
+	Meta             struct {
+		ExtraDownloads []cratesDownload `json:"extra_downloads"`
+	} `json:"meta"`
+}
+
+func (p *CratesProvider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
+	var user cratesUserResponse
+	if err := getJSON(ctx, p.client, fmt.Sprintf("%s/users/%s", p.baseURL, url.PathEscape(handle)), &user); err != nil {
+		return core.DevStats{}, fmt.Errorf("crates: fetch user: %w", err)
+	}
+
+	names, err := p.fetchCrateNames(ctx, user.User.ID)
+	if err != nil {
+		return core.DevStats{}, fmt.Errorf("crates: list crates: %w", err)
+	}
+
+	since := time.Now().UTC().Add(-downloadWindow)
+	pkgs := make([]core.PackageStat, 0, len(names))
+	failed := 0
+
+	for _, name := range names {
+		downloads, err := p.fetchRecentDownloads(ctx, name, since)
+		if err != nil {
+			log.Printf("crates: fetchRecentDownloads error for %s: %v", name, err)
+			failed++
+			continue
+		}
+		pkgs = append(pkgs, core.PackageStat{
+			Name:      name,
+			Registry:  "crates.io",
+			Downloads: downloads,
+		})
+	}
+
+	return summarize("crates.io", pkgs, failed, len(names)), nil
+}
+
+func (p *CratesProvider) fetchCrateNames(ctx context.Context, userID int) ([]string, error) {
+	var names []string
+
+	for page := 1; ; page++ {
+		endpoint := fmt.Sprintf("%s/crates?user_id=%d&per_page=%d&page=%d", p.baseURL, userID, cratesPageSize, page)
+
+		var r cratesListResponse
+		if err := getJSON(ctx, p.client, endpoint, &r); err != nil {
+			return nil, err
+		}
+
+		for _, c := range r.Crates {
+			names = append(names, c.Name)
+		}
+
+		if len(r.Crates) < cratesPageSize || len(names) >= r.Meta.Total {
+			break
+		}
+	}
+
+	return names, nil
+}
+
+func (p *CratesProvider) fetchRecentDownloads(ctx context.Context, name string, since time.Time) (int64, error) {
+	endpoint := fmt.Sprintf("%s/crates/%s/downloads", p.baseURL, url.PathEscape(name))
+
+	var r cratesDownloadsResponse
+	if err := getJSON(ctx, p.client, endpoint, &r); err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, list := range [][]cratesDownload{r.VersionDownloads, r.Meta.ExtraDownloads} {
+		for _, d := range list {
+			day, err := time.Parse(cratesDateLayout, d.Date)
+			if err != nil || day.Before(since) {
+				continue
+			}
+			total += d.Downloads
+		}
+	}
+
+	return total, nil
+}
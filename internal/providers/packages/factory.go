@@ -0,0 +1,84 @@
+package packages
+
+import (
+	"fmt"
+
+	"github.com/vukan322/devmetrics/internal/providers"
+)
+
+func NPMFactory() providers.Factory {
+	return providers.Factory{
+		Name:   "npm",
+		Title:  "npm",
+		Prefix: "DEV_METRICS_NPM",
+		Keys: []providers.Key{
+			{Name: "USER", Required: true, Usage: "npm maintainer username"},
+			{Name: "REGISTRY_URL", Default: defaultNPMRegistryURL, Usage: "registry URL used for package search"},
+			{Name: "DOWNLOADS_URL", Default: defaultNPMDownloadsURL, Usage: "download counts API URL"},
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			return NewNPM(cfg.Get("REGISTRY_URL"), cfg.Get("DOWNLOADS_URL")), cfg.Get("USER"), nil
+		},
+	}
+}
+
+func PyPIFactory() providers.Factory {
+	return providers.Factory{
+		Name:   "pypi",
+		Title:  "PyPI",
+		Prefix: "DEV_METRICS_PYPI",
+		Keys: []providers.Key{
+			{Name: "USER", Usage: "PyPI username whose packages are listed"},
+			{Name: "PACKAGES", Usage: "comma-separated package names, added to the user's packages"},
+			{Name: "URL", Default: defaultPyPIURL, Usage: "PyPI XML-RPC URL used to list the user's packages"},
+			{Name: "STATS_URL", Default: defaultPyPIStatsURL, Usage: "pypistats API URL"},
+		},
+		Validate: func(cfg providers.Config) error {
+			if cfg.Get("USER") == "" && len(cfg.List("PACKAGES")) == 0 {
+				return fmt.Errorf("set DEV_METRICS_PYPI_USER or DEV_METRICS_PYPI_PACKAGES")
+			}
+			return nil
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			return NewPyPI(cfg.Get("URL"), cfg.Get("STATS_URL"), cfg.List("PACKAGES")), cfg.Get("USER"), nil
+		},
+	}
+}
+
+func CratesFactory() providers.Factory {
+	return providers.Factory{
+		Name:   "crates",
+		Title:  "crates.io",
+		Prefix: "DEV_METRICS_CRATES",
+		Keys: []providers.Key{
+			{Name: "USER", Required: true, Usage: "crates.io login"},
+			{Name: "URL", Default: defaultCratesURL, Usage: "crates.io API URL"},
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			return NewCrates(cfg.Get("URL")), cfg.Get("USER"), nil
+		},
+	}
+}
+
+func GoProxyFactory() providers.Factory {
+	return providers.Factory{
+		Name:   "goproxy",
+		Title:  "Go modules",
+		Prefix: "DEV_METRICS_GO",
+		Keys: []providers.Key{
+			{Name: "GITHUB_USER", Usage: "GitHub user whose github.com/<user>/<repo> modules are looked up on the proxy"},
+			{Name: "MODULES", Usage: "comma-separated module paths, added to the GitHub user's modules"},
+			{Name: "PROXY_URL", Aliases: []string{"DEV_METRICS_GOPROXY_URL"}, Default: defaultGoProxyURL, Usage: "module proxy URL"},
+			{Name: "GITHUB_URL", Default: defaultGoGitHubURL, Usage: "GitHub API URL used to list the user's repositories"},
+		},
+		Validate: func(cfg providers.Config) error {
+			if cfg.Get("GITHUB_USER") == "" && len(cfg.List("MODULES")) == 0 {
+				return fmt.Errorf("set DEV_METRICS_GO_GITHUB_USER or DEV_METRICS_GO_MODULES")
+			}
+			return nil
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			return NewGoProxy(cfg.Get("PROXY_URL"), cfg.Get("GITHUB_URL"), cfg.List("MODULES")), cfg.Get("GITHUB_USER"), nil
+		},
+	}
+}
@@ -0,0 +1,157 @@
+package packages
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/vukan322/devmetrics/internal/core"
+)
+
+const (
+	defaultGoProxyURL  = "https://proxy.golang.org"
+	defaultGoGitHubURL = "https://api.github.com"
+	goGitHubPageSize   = 100
+	goGitHubModuleRoot = "github.com/"
+)
+
+type GoProxyProvider struct {
+	client    *http.Client
+	proxyURL  string
+	githubURL string
+	modules   []string
+}
+
+type goGitHubRepo struct {
+	Name string `json:"name"`
+	Fork bool   `json:"fork"`
+}
+
+func NewGoProxy(proxyURL, githubURL string, modules []string) *GoProxyProvider {
+	if proxyURL == "" {
+		proxyURL = defaultGoProxyURL
+	}
+	if githubURL == "" {
+		githubURL = defaultGoGitHubURL
+	}
+
+	return &GoProxyProvider{
+		client:    newClient(),
+		proxyURL:  strings.TrimRight(proxyURL, "/"),
+		githubURL: strings.TrimRight(githubURL, "/"),
+		modules:   modules,
+	}
+}
+
+func (p *GoProxyProvider) Name() string {
+	return "goproxy"
+}
+
+func (p *GoProxyProvider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
+	modules := appendUnique(nil, p.modules...)
+	listed := len(modules)
+	if handle != "" {
+		repos, err := p.fetchGitHubModules(ctx, handle)
+		if err != nil {
+			return core.DevStats{}, fmt.Errorf("goproxy: list github repos of %s: %w", handle, err)
+		}
+		modules = appendUnique(modules, repos...)
+	}
+
+	pkgs := make([]core.PackageStat, 0, len(modules))
+	failed := 0
+
+	for i, module := range modules {
+		published, err := p.isPublished(ctx, module)
+		if err != nil {
+			log.Printf("goproxy: lookup error for %s: %v", module, err)
+			failed++
+			continue
+		}
+		if !published {
+			if i < listed {
+				log.Printf("goproxy: module %s has no published versions", module)
+			}
+			continue
+		}
+
+		pkgs = append(pkgs, core.PackageStat{
+			Name:             module,
+			Registry:         "go",
+			DownloadsUnknown: true,
+		})
+	}
+
+	return summarize("go", pkgs, failed, len(modules)), nil
+}
+
+func (p *GoProxyProvider) fetchGitHubModules(ctx context.Context, user string) ([]string, error) {
+	var modules []string
+
+	for page := 1; ; page++ {
+		endpoint := fmt.Sprintf("%s/users/%s/repos?type=owner&per_page=%d&page=%d", p.githubURL, url.PathEscape(user), goGitHubPageSize, page)
+
+		var repos []goGitHubRepo
+		if err := getJSON(ctx, p.client, endpoint, &repos); err != nil {
+			return nil, err
+		}
+
+		for _, r := range repos {
+			if !r.Fork {
+				modules = append(modules, goGitHubModuleRoot+user+"/"+r.Name)
+			}
+		}
+
+		if len(repos) < goGitHubPageSize {
+			return modules, nil
+		}
+	}
+}
+
+func (p *GoProxyProvider) isPublished(ctx context.Context, module string) (bool, error) {
+	endpoint := fmt.Sprintf("%s/%s/@v/list", p.proxyURL, escapeModulePath(module))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("User-Agent", defaultUserAgent)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return false, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return false, fmt.Errorf("read response: %w", err)
+	}
+
+	return strings.TrimSpace(string(body)) != "", nil
+}
+
+func escapeModulePath(module string) string {
+	var b strings.Builder
+	for _, r := range module {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
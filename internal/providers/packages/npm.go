@@ -0,0 +1,121 @@
+package packages
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/vukan322/devmetrics/internal/core"
+)
+
+const (
+	defaultNPMRegistryURL  = "https://registry.npmjs.org"
+	defaultNPMDownloadsURL = "https://api.npmjs.org"
+	npmSearchPageSize      = 250
+)
+
+type NPMProvider struct {
+	client       *http.Client
+	registryURL  string
+	downloadsURL string
+}
+
+func NewNPM(registryURL, downloadsURL string) *NPMProvider {
+	if registryURL == "" {
+		registryURL = defaultNPMRegistryURL
+	}
+	if downloadsURL == "" {
+		downloadsURL = defaultNPMDownloadsURL
+	}
+
+	return &NPMProvider{
+		client:       newClient(),
+		registryURL:  strings.TrimRight(registryURL, "/"),
+		downloadsURL: strings.TrimRight(downloadsURL, "/"),
+	}
+}
+
+func (p *NPMProvider) Name() string {
+	return "npm"
+}
+
+type npmSearchResponse struct {
+	Objects []struct {
+		Package struct {
+			Name string `json:"name"`
+		} `json:"package"`
+	} `json:"objects"`
+	Total int `json:"total"`
+}
+
+type npmDownloadsResponse struct {
+	Downloads int64 `json:"downloads"`
+}
+
+func (p *NPMProvider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
+	names, err := p.fetchPackageNames(ctx, handle)
+	if err != nil {
+		return core.DevStats{}, fmt.Errorf("npm: search packages: %w", err)
+	}
+
+	pkgs := make([]core.PackageStat, 0, len(names))
+	failed := 0
+	for _, name := range names {
+		downloads, err := p.fetchDownloads(ctx, name)
+		if err != nil {
+			log.Printf("npm: fetchDownloads error for %s: %v", name, err)
+			failed++
+			continue
+		}
+		pkgs = append(pkgs, core.PackageStat{
+			Name:      name,
+			Registry:  "npm",
+			Downloads: downloads,
+		})
+	}
+
+	return summarize("npm", pkgs, failed, len(names)), nil
+}
+
+func (p *NPMProvider) fetchPackageNames(ctx context.Context, maintainer string) ([]string, error) {
+	var names []string
+
+	for from := 0; ; from += npmSearchPageSize {
+		endpoint := fmt.Sprintf(
+			"%s/-/v1/search?text=%s&size=%d&from=%d",
+			p.registryURL,
+			url.QueryEscape("maintainer:"+maintainer),
+			npmSearchPageSize,
+			from,
+		)
+
+		var page npmSearchResponse
+		if err := getJSON(ctx, p.client, endpoint, &page); err != nil {
+			return nil, err
+		}
+
+		for _, obj := range page.Objects {
+			names = append(names, obj.Package.Name)
+		}
+
+		if len(page.Objects) < npmSearchPageSize || len(names) >= page.Total {
+			break
+		}
+	}
+
+	return names, nil
+}
+
+func (p *NPMProvider) fetchDownloads(ctx context.Context, name string) (int64, error) {
+	endpoint := fmt.Sprintf("%s/downloads/point/last-month/%s", p.downloadsURL, name)
+
+	var r npmDownloadsResponse
+	if err := getJSON(ctx, p.client, endpoint, &r); err != nil {
+		return 0, err
+	}
+
+	return r.Downloads, nil
+}
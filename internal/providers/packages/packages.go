@@ -0,0 +1,77 @@
+package packages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/vukan322/devmetrics/internal/core"
+	"github.com/vukan322/devmetrics/internal/httpclient"
+)
+
+const (
+	defaultUserAgent = "devmetrics/0.1 (https://github.com/vukan322/devmetrics)"
+	downloadWindow   = 30 * 24 * time.Hour
+)
+
+var errNotFound = errors.New("not found")
+
+func newClient() *http.Client {
+	return httpclient.New()
+}
+
+func getJSON(ctx context.Context, client *http.Client, endpoint string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", defaultUserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", endpoint, errNotFound)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response from %s: %w", endpoint, err)
+	}
+
+	return nil
+}
+
+func summarize(registry string, pkgs []core.PackageStat, failed, total int) core.DevStats {
+	sort.SliceStable(pkgs, func(i, j int) bool {
+		return pkgs[i].Downloads > pkgs[j].Downloads
+	})
+
+	var downloads int64
+	for _, pkg := range pkgs {
+		downloads += pkg.Downloads
+	}
+
+	var diag core.Diagnostics
+	diag.RecordPartial(registry, core.FieldPackages, failed, total)
+	diag.Unsupported(registry, core.FieldRepos, core.FieldStars, core.FieldFollowers, core.FieldContributedRepos, core.FieldJoined, core.FieldLanguages, core.FieldContributions, core.FieldIssues, core.FieldPullRequests, core.FieldReviews)
+
+	return core.DevStats{
+		Packages: core.Packages{
+			Count:          len(pkgs),
+			TotalDownloads: downloads,
+			Top:            pkgs,
+		},
+		Diagnostics: diag,
+	}
+}
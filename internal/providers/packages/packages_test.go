@@ -0,0 +1,191 @@
+package packages
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vukan322/devmetrics/internal/core"
+)
+
+func packageNames(stats core.DevStats) []string {
+	var names []string
+	for _, pkg := range stats.Packages.Top {
+		names = append(names, pkg.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func failures(stats core.DevStats) []string {
+	var out []string
+	for _, r := range stats.Diagnostics.Failures() {
+		out = append(out, r.Error)
+	}
+	return out
+}
+
+func TestGoProxyDiscoversGitHubModules(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		probed []string
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /github/users/ada/repos", func(w http.ResponseWriter, r *http.Request) {
+		var repos []string
+		if r.URL.Query().Get("page") == "1" {
+			for i := range goGitHubPageSize {
+				repos = append(repos, fmt.Sprintf(`{"name": "r%d"}`, i))
+			}
+		} else {
+			repos = []string{`{"name": "engine"}`, `{"name": "linux", "fork": true}`}
+		}
+		io.WriteString(w, "["+strings.Join(repos, ",")+"]")
+	})
+	mux.HandleFunc("GET /proxy/", func(w http.ResponseWriter, r *http.Request) {
+		module := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/proxy/"), "/@v/list")
+		mu.Lock()
+		probed = append(probed, module)
+		mu.Unlock()
+
+		switch module {
+		case "github.com/ada/engine":
+			io.WriteString(w, "v1.0.0\nv1.1.0\n")
+		case "github.com/ada/r7", "gitlab.com/ada/!tool":
+			io.WriteString(w, "v0.1.0\n")
+		case "github.com/ada/r13":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "example.com/gone":
+			w.WriteHeader(http.StatusGone)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	p := NewGoProxy(srv.URL+"/proxy", srv.URL+"/github", []string{"gitlab.com/ada/Tool", "example.com/gone", "github.com/ada/engine"})
+	p.client = srv.Client()
+
+	stats, err := p.Fetch(context.Background(), "ada")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	want := []string{"github.com/ada/engine", "github.com/ada/r7", "gitlab.com/ada/Tool"}
+	if got := packageNames(stats); !reflect.DeepEqual(got, want) {
+		t.Errorf("packages = %v, want %v", got, want)
+	}
+	for _, pkg := range stats.Packages.Top {
+		if !pkg.DownloadsUnknown || pkg.Registry != "go" {
+			t.Errorf("package %s = %+v, want registry go with unknown downloads", pkg.Name, pkg)
+		}
+	}
+
+	if got, want := failures(stats), []string{"1 of 103 requests failed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("failures = %v, want %v", got, want)
+	}
+
+	seen := make(map[string]int)
+	for _, module := range probed {
+		seen[module]++
+	}
+	if seen["github.com/ada/linux"] != 0 {
+		t.Errorf("probed the fork github.com/ada/linux")
+	}
+	if seen["github.com/ada/engine"] != 1 {
+		t.Errorf("probed github.com/ada/engine %d times, want once", seen["github.com/ada/engine"])
+	}
+}
+
+func TestGoProxyListedModulesOnly(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/github/") {
+			t.Errorf("listed GitHub repos without a user: %s", r.URL)
+		}
+		io.WriteString(w, "v1.0.0\n")
+	}))
+	defer srv.Close()
+
+	p := NewGoProxy(srv.URL, srv.URL+"/github", []string{"example.com/lib"})
+	p.client = srv.Client()
+
+	stats, err := p.Fetch(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if got, want := packageNames(stats), []string{"example.com/lib"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("packages = %v, want %v", got, want)
+	}
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	tests := map[string]string{
+		"github.com/ada/engine":       "github.com/ada/engine",
+		"github.com/Ada/BurntSushi":   "github.com/!ada/!burnt!sushi",
+		"example.com/lib/v2":          "example.com/lib/v2",
+		"gitlab.com/ada/Tool/Sub.Mod": "gitlab.com/ada/!tool/!sub.!mod",
+	}
+
+	for in, want := range tests {
+		if got := escapeModulePath(in); got != want {
+			t.Errorf("escapeModulePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPyPIDiscoversUserPackages(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /pypi", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "<methodName>user_packages</methodName>") || !strings.Contains(string(body), "<string>ada</string>") {
+			t.Errorf("unexpected XML-RPC call: %s", body)
+		}
+		io.WriteString(w, `<?xml version="1.0"?>
+<methodResponse><params><param><value><array><data>
+<value><array><data><value><string>Owner</string></value><value><string>fastgrid</string></value></data></array></value>
+<value><array><data><value><string>Maintainer</string></value><value><string>Tinyhttp</string></value></data></array></value>
+<value><array><data><value><string>Owner</string></value><value><string>broken</string></value></data></array></value>
+</data></array></value></param></params></methodResponse>`)
+	})
+	mux.HandleFunc("GET /stats/packages/{name}/recent", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("name") {
+		case "fastgrid":
+			io.WriteString(w, `{"data": {"last_month": 1200}}`)
+		case "tinyhttp":
+			io.WriteString(w, `{"data": {"last_month": 300}}`)
+		case "extra":
+			io.WriteString(w, `{"data": {"last_month": 50}}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	p := NewPyPI(srv.URL+"/pypi", srv.URL+"/stats", []string{"extra", "FastGrid"})
+	p.client = srv.Client()
+
+	stats, err := p.Fetch(context.Background(), "ada")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if got, want := packageNames(stats), []string{"FastGrid", "Tinyhttp", "extra"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("packages = %v, want %v", got, want)
+	}
+	if stats.Packages.TotalDownloads != 1550 {
+		t.Errorf("TotalDownloads = %d, want 1550", stats.Packages.TotalDownloads)
+	}
+	if got, want := failures(stats), []string{"1 of 4 requests failed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("failures = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,150 @@
+package packages
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/vukan322/devmetrics/internal/core"
+)
+
+const (
+	defaultPyPIURL      = "https://pypi.org/pypi"
+	defaultPyPIStatsURL = "https://pypistats.org/api"
+)
+
+type PyPIProvider struct {
+	client   *http.Client
+	baseURL  string
+	statsURL string
+	packages []string
+}
+
+func NewPyPI(baseURL, statsURL string, packages []string) *PyPIProvider {
+	if baseURL == "" {
+		baseURL = defaultPyPIURL
+	}
+	if statsURL == "" {
+		statsURL = defaultPyPIStatsURL
+	}
+
+	return &PyPIProvider{
+		client:   newClient(),
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		statsURL: strings.TrimRight(statsURL, "/"),
+		packages: packages,
+	}
+}
+
+func (p *PyPIProvider) Name() string {
+	return "pypi"
+}
+
+type pypiRecentResponse struct {
+	Data struct {
+		LastMonth int64 `json:"last_month"`
+	} `json:"data"`
+}
+
+type pypiUserPackagesResponse struct {
+	Fault *struct{} `xml:"fault"`
+	Roles []struct {
+		Values []string `xml:"array>data>value>string"`
+	} `xml:"params>param>value>array>data>value"`
+}
+
+func (p *PyPIProvider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
+	names := appendUnique(nil, p.packages...)
+	if handle != "" {
+		owned, err := p.fetchUserPackages(ctx, handle)
+		if err != nil {
+			return core.DevStats{}, fmt.Errorf("pypi: list packages of %s: %w", handle, err)
+		}
+		names = appendUnique(names, owned...)
+	}
+
+	pkgs := make([]core.PackageStat, 0, len(names))
+	failed := 0
+
+	for _, name := range names {
+		endpoint := fmt.Sprintf("%s/packages/%s/recent", p.statsURL, url.PathEscape(strings.ToLower(name)))
+
+		var r pypiRecentResponse
+		if err := getJSON(ctx, p.client, endpoint, &r); err != nil {
+			log.Printf("pypi: fetch downloads error for %s: %v", name, err)
+			failed++
+			continue
+		}
+
+		pkgs = append(pkgs, core.PackageStat{
+			Name:      name,
+			Registry:  "pypi",
+			Downloads: r.Data.LastMonth,
+		})
+	}
+
+	return summarize("pypi", pkgs, failed, len(names)), nil
+}
+
+func (p *PyPIProvider) fetchUserPackages(ctx context.Context, user string) ([]string, error) {
+	var body bytes.Buffer
+	body.WriteString(`<?xml version="1.0"?><methodCall><methodName>user_packages</methodName><params><param><value><string>`)
+	if err := xml.EscapeText(&body, []byte(user)); err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+	body.WriteString(`</string></value></param></params></methodCall>`)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL, &body)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/xml")
+	req.Header.Set("User-Agent", defaultUserAgent)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, p.baseURL)
+	}
+
+	var r pypiUserPackagesResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("decode response from %s: %w", p.baseURL, err)
+	}
+	if r.Fault != nil {
+		return nil, fmt.Errorf("xml-rpc fault from %s", p.baseURL)
+	}
+
+	var names []string
+	for _, role := range r.Roles {
+		if len(role.Values) == 2 {
+			names = appendUnique(names, role.Values[1])
+		}
+	}
+	return names, nil
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range list {
+			if strings.EqualFold(existing, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -10,3 +10,7 @@ type Provider interface {
 	Name() string
 	Fetch(ctx context.Context, handle string) (core.DevStats, error)
 }
+
+type RepoFilterer interface {
+	SetRepoFilter(f core.RepoFilter)
+}
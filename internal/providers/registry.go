@@ -0,0 +1,266 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vukan322/devmetrics/internal/core"
+)
+
+type Key struct {
+	Name         string
+	Aliases      []string
+	Required     bool
+	Default      string
+	UserFallback bool
+	Usage        string
+}
+
+type Factory struct {
+	Name     string
+	Title    string
+	Prefix   string
+	Keys     []Key
+	Multiple bool
+	OptIn    bool
+	Validate func(cfg Config) error
+	New      func(cfg Config) (Provider, string, error)
+}
+
+type Config map[string]string
+
+func (c Config) Get(name string) string {
+	return c[name]
+}
+
+func (c Config) List(name string) []string {
+	var out []string
+	for _, part := range strings.Split(c[name], ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+type Instance struct {
+	Factory  Factory
+	Index    int
+	Provider Provider
+	Handle   string
+	Timeout  time.Duration
+}
+
+func (i Instance) Label() string {
+	if i.Index <= 1 {
+		return i.Factory.Title
+	}
+	return fmt.Sprintf("%s #%d", i.Factory.Title, i.Index)
+}
+
+type LoadOptions struct {
+	Lookup         func(env string) string
+	User           string
+	Selected       []string
+	DefaultTimeout time.Duration
+	RepoFilter     core.RepoFilter
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []Factory
+)
+
+func Register(f Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, existing := range registry {
+		if existing.Name == f.Name {
+			panic(fmt.Sprintf("providers: factory %q registered twice", f.Name))
+		}
+	}
+	registry = append(registry, f)
+}
+
+func Factories() []Factory {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	out := make([]Factory, len(registry))
+	copy(out, registry)
+	return out
+}
+
+func CheckSelected(names []string) error {
+	known := make(map[string]bool)
+	var all []string
+	for _, f := range Factories() {
+		known[f.Name] = true
+		all = append(all, f.Name)
+	}
+
+	var unknown []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && !known[name] {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("providers: unknown provider %s, expected one of %s", strings.Join(unknown, ", "), strings.Join(all, ", "))
+}
+
+func (f Factory) EnvName(key Key, index int) string {
+	name := f.Prefix + "_" + key.Name
+	if index > 1 {
+		name += fmt.Sprintf("_%d", index)
+	}
+	return name
+}
+
+func (f Factory) TimeoutEnvName(index int) string {
+	return f.EnvName(Key{Name: "TIMEOUT"}, index)
+}
+
+func Load(opts LoadOptions) ([]Instance, []error) {
+	selected := make(map[string]bool, len(opts.Selected))
+	for _, name := range opts.Selected {
+		selected[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	var (
+		instances []Instance
+		errs      []error
+	)
+
+	for _, f := range Factories() {
+		if len(selected) > 0 && !selected[f.Name] {
+			continue
+		}
+		if len(selected) == 0 && f.OptIn {
+			continue
+		}
+
+		for index := 1; ; index++ {
+			cfg, touched := f.resolve(opts, index)
+			if !touched && index > 1 {
+				break
+			}
+
+			inst, ok, err := f.build(cfg, touched, index)
+			if err == nil && ok {
+				inst.Timeout, err = f.timeout(opts, index)
+				if rf, filterable := inst.Provider.(RepoFilterer); filterable {
+					rf.SetRepoFilter(opts.RepoFilter)
+				}
+			}
+			if err != nil {
+				errs = append(errs, err)
+			} else if ok {
+				instances = append(instances, inst)
+			}
+
+			if !f.Multiple {
+				break
+			}
+		}
+	}
+
+	return instances, errs
+}
+
+func (f Factory) resolve(opts LoadOptions, index int) (Config, bool) {
+	cfg := make(Config, len(f.Keys))
+	touched := false
+
+	for _, key := range f.Keys {
+		value := opts.Lookup(f.EnvName(key, index))
+		if value == "" && index == 1 {
+			for _, alias := range key.Aliases {
+				if value = opts.Lookup(alias); value != "" {
+					break
+				}
+			}
+		}
+		if value != "" {
+			touched = true
+		}
+		if value == "" && key.UserFallback {
+			value = opts.User
+		}
+		if value == "" {
+			value = key.Default
+		}
+		cfg[key.Name] = value
+	}
+
+	return cfg, touched
+}
+
+func (f Factory) timeout(opts LoadOptions, index int) (time.Duration, error) {
+	env := f.TimeoutEnvName(index)
+
+	value := opts.Lookup(env)
+	if value == "" {
+		return opts.DefaultTimeout, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s: invalid %s %q, expected a positive duration like 30s", f.Name, env, value)
+	}
+
+	return d, nil
+}
+
+func (f Factory) build(cfg Config, touched bool, index int) (Instance, bool, error) {
+	required := false
+	var missing []string
+	for _, key := range f.Keys {
+		if !key.Required {
+			continue
+		}
+		required = true
+		if cfg[key.Name] == "" {
+			missing = append(missing, f.EnvName(key, index))
+		}
+	}
+
+	if !touched && !required {
+		return Instance{}, false, nil
+	}
+
+	if len(missing) > 0 {
+		if !touched {
+			return Instance{}, false, nil
+		}
+		sort.Strings(missing)
+		return Instance{}, false, fmt.Errorf("%s: missing required configuration %s", f.Name, strings.Join(missing, ", "))
+	}
+
+	if f.Validate != nil {
+		if err := f.Validate(cfg); err != nil {
+			return Instance{}, false, fmt.Errorf("%s: invalid configuration: %w", f.Name, err)
+		}
+	}
+
+	p, handle, err := f.New(cfg)
+	if err != nil {
+		return Instance{}, false, fmt.Errorf("%s: %w", f.Name, err)
+	}
+
+	return Instance{
+		Factory:  f,
+		Index:    index,
+		Provider: p,
+		Handle:   handle,
+	}, true, nil
+}
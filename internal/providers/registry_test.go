@@ -0,0 +1,338 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vukan322/devmetrics/internal/core"
+)
+
+type fakeProvider struct {
+	name   string
+	cfg    Config
+	filter core.RepoFilter
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
+	return core.DevStats{}, nil
+}
+
+func (p *fakeProvider) SetRepoFilter(f core.RepoFilter) { p.filter = f }
+
+func fakeFactory(name string, keys ...Key) Factory {
+	return Factory{
+		Name:   name,
+		Title:  strings.ToUpper(name[:1]) + name[1:],
+		Prefix: "DEV_METRICS_" + strings.ToUpper(name),
+		Keys:   keys,
+		New: func(cfg Config) (Provider, string, error) {
+			return &fakeProvider{name: name, cfg: cfg}, cfg.Get("USER"), nil
+		},
+	}
+}
+
+func withFactories(t *testing.T, factories ...Factory) {
+	t.Helper()
+	registryMu.Lock()
+	saved := registry
+	registry = factories
+	registryMu.Unlock()
+
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = saved
+		registryMu.Unlock()
+	})
+}
+
+func lookup(env map[string]string) func(string) string {
+	return func(name string) string { return env[name] }
+}
+
+func TestLoadResolvesInstances(t *testing.T) {
+	forge := fakeFactory("forge",
+		Key{Name: "URL", Required: true},
+		Key{Name: "USER", Required: true, UserFallback: true},
+		Key{Name: "TOKEN", Aliases: []string{"FORGE_TOKEN"}},
+		Key{Name: "SCOPE", Default: "public"},
+	)
+	forge.Multiple = true
+
+	single := fakeFactory("single", Key{Name: "USER", Required: true, UserFallback: true})
+
+	optional := fakeFactory("optional", Key{Name: "USER"}, Key{Name: "PACKAGES"})
+
+	extra := fakeFactory("extra", Key{Name: "USER", Required: true, UserFallback: true})
+	extra.OptIn = true
+
+	type want struct {
+		label  string
+		handle string
+		cfg    Config
+	}
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		user     string
+		selected []string
+		want     []want
+		wantErrs []string
+	}{
+		{
+			name: "user fallback only",
+			user: "ada",
+			want: []want{{label: "Single", handle: "ada"}},
+		},
+		{
+			name: "suffixed instances fall back to user",
+			user: "ada",
+			env: map[string]string{
+				"DEV_METRICS_FORGE_URL":     "https://one.example.com",
+				"DEV_METRICS_FORGE_URL_2":   "https://two.example.com",
+				"DEV_METRICS_FORGE_USER_2":  "lovelace",
+				"DEV_METRICS_FORGE_URL_3":   "https://three.example.com",
+				"DEV_METRICS_FORGE_SCOPE_3": "all",
+			},
+			want: []want{
+				{label: "Forge", handle: "ada", cfg: Config{"URL": "https://one.example.com", "USER": "ada", "TOKEN": "", "SCOPE": "public"}},
+				{label: "Forge #2", handle: "lovelace", cfg: Config{"URL": "https://two.example.com", "USER": "lovelace", "TOKEN": "", "SCOPE": "public"}},
+				{label: "Forge #3", handle: "ada", cfg: Config{"URL": "https://three.example.com", "USER": "ada", "TOKEN": "", "SCOPE": "all"}},
+				{label: "Single", handle: "ada"},
+			},
+		},
+		{
+			name: "stops at the first untouched suffix",
+			user: "ada",
+			env: map[string]string{
+				"DEV_METRICS_FORGE_URL":   "https://one.example.com",
+				"DEV_METRICS_FORGE_URL_3": "https://three.example.com",
+			},
+			want: []want{
+				{label: "Forge", handle: "ada"},
+				{label: "Single", handle: "ada"},
+			},
+		},
+		{
+			name: "aliases apply to the first instance only",
+			user: "ada",
+			env: map[string]string{
+				"FORGE_TOKEN":             "secret",
+				"DEV_METRICS_FORGE_URL":   "https://one.example.com",
+				"DEV_METRICS_FORGE_URL_2": "https://two.example.com",
+			},
+			want: []want{
+				{label: "Forge", handle: "ada", cfg: Config{"URL": "https://one.example.com", "USER": "ada", "TOKEN": "secret", "SCOPE": "public"}},
+				{label: "Forge #2", handle: "ada", cfg: Config{"URL": "https://two.example.com", "USER": "ada", "TOKEN": "", "SCOPE": "public"}},
+				{label: "Single", handle: "ada"},
+			},
+		},
+		{
+			name: "missing required keys on a touched instance",
+			env: map[string]string{
+				"DEV_METRICS_FORGE_TOKEN":  "secret",
+				"DEV_METRICS_FORGE_URL_2":  "https://two.example.com",
+				"DEV_METRICS_FORGE_USER_2": "lovelace",
+			},
+			want: []want{{label: "Forge #2", handle: "lovelace"}},
+			wantErrs: []string{
+				"forge: missing required configuration DEV_METRICS_FORGE_URL, DEV_METRICS_FORGE_USER",
+			},
+		},
+		{
+			name: "optional keys alone",
+			env:  map[string]string{"DEV_METRICS_OPTIONAL_PACKAGES": "left-pad"},
+			want: []want{{label: "Optional", cfg: Config{"USER": "", "PACKAGES": "left-pad"}}},
+		},
+		{
+			name:     "selected includes opt-in",
+			user:     "ada",
+			selected: []string{" Extra ", "single"},
+			want: []want{
+				{label: "Single", handle: "ada"},
+				{label: "Extra", handle: "ada"},
+			},
+		},
+		{
+			name:     "selected skips others",
+			user:     "ada",
+			env:      map[string]string{"DEV_METRICS_FORGE_URL": "https://one.example.com"},
+			selected: []string{"forge"},
+			want:     []want{{label: "Forge", handle: "ada"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withFactories(t, forge, single, optional, extra)
+
+			instances, errs := Load(LoadOptions{
+				Lookup:   lookup(tt.env),
+				User:     tt.user,
+				Selected: tt.selected,
+			})
+
+			var gotErrs []string
+			for _, err := range errs {
+				gotErrs = append(gotErrs, err.Error())
+			}
+			if !reflect.DeepEqual(gotErrs, tt.wantErrs) {
+				t.Errorf("errors = %q, want %q", gotErrs, tt.wantErrs)
+			}
+
+			if len(instances) != len(tt.want) {
+				t.Fatalf("got %d instances, want %d", len(instances), len(tt.want))
+			}
+			for i, w := range tt.want {
+				inst := instances[i]
+				if inst.Label() != w.label || inst.Handle != w.handle {
+					t.Errorf("instance %d = %s (%q), want %s (%q)", i, inst.Label(), inst.Handle, w.label, w.handle)
+				}
+				if w.cfg == nil {
+					continue
+				}
+				if got := inst.Provider.(*fakeProvider).cfg; !reflect.DeepEqual(got, w.cfg) {
+					t.Errorf("instance %d config = %v, want %v", i, got, w.cfg)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadTimeouts(t *testing.T) {
+	forge := fakeFactory("forge", Key{Name: "USER", Required: true})
+	forge.Multiple = true
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    []time.Duration
+		wantErr string
+	}{
+		{
+			name: "default",
+			env:  map[string]string{"DEV_METRICS_FORGE_USER": "ada"},
+			want: []time.Duration{time.Minute},
+		},
+		{
+			name: "per instance",
+			env: map[string]string{
+				"DEV_METRICS_FORGE_USER":      "ada",
+				"DEV_METRICS_FORGE_USER_2":    "lovelace",
+				"DEV_METRICS_FORGE_TIMEOUT_2": "90s",
+			},
+			want: []time.Duration{time.Minute, 90 * time.Second},
+		},
+		{
+			name: "invalid",
+			env: map[string]string{
+				"DEV_METRICS_FORGE_USER":    "ada",
+				"DEV_METRICS_FORGE_TIMEOUT": "soon",
+			},
+			wantErr: `forge: invalid DEV_METRICS_FORGE_TIMEOUT "soon", expected a positive duration like 30s`,
+		},
+		{
+			name: "not positive",
+			env: map[string]string{
+				"DEV_METRICS_FORGE_USER":    "ada",
+				"DEV_METRICS_FORGE_TIMEOUT": "-5s",
+			},
+			wantErr: `forge: invalid DEV_METRICS_FORGE_TIMEOUT "-5s", expected a positive duration like 30s`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withFactories(t, forge)
+
+			instances, errs := Load(LoadOptions{Lookup: lookup(tt.env), DefaultTimeout: time.Minute})
+
+			if tt.wantErr != "" {
+				if len(errs) != 1 || errs[0].Error() != tt.wantErr {
+					t.Fatalf("errors = %v, want %q", errs, tt.wantErr)
+				}
+				if len(instances) != 0 {
+					t.Errorf("got %d instances, want none", len(instances))
+				}
+				return
+			}
+
+			if len(errs) > 0 {
+				t.Fatalf("errors = %v", errs)
+			}
+			var got []time.Duration
+			for _, inst := range instances {
+				got = append(got, inst.Timeout)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("timeouts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadValidatesAndFilters(t *testing.T) {
+	invalid := errors.New("token needs a URL")
+
+	forge := fakeFactory("forge", Key{Name: "USER", Required: true}, Key{Name: "TOKEN"})
+	forge.Validate = func(cfg Config) error {
+		if cfg.Get("TOKEN") == "bad" {
+			return invalid
+		}
+		return nil
+	}
+
+	filter := core.RepoFilter{Exclude: []string{"ada/archive"}}
+
+	withFactories(t, forge)
+
+	_, errs := Load(LoadOptions{Lookup: lookup(map[string]string{
+		"DEV_METRICS_FORGE_USER":  "ada",
+		"DEV_METRICS_FORGE_TOKEN": "bad",
+	})})
+	if len(errs) != 1 || !errors.Is(errs[0], invalid) {
+		t.Errorf("errors = %v, want the validation error", errs)
+	}
+
+	instances, errs := Load(LoadOptions{
+		Lookup:     lookup(map[string]string{"DEV_METRICS_FORGE_USER": "ada"}),
+		RepoFilter: filter,
+	})
+	if len(errs) > 0 || len(instances) != 1 {
+		t.Fatalf("got %d instances and errors %v, want 1 instance", len(instances), errs)
+	}
+	if got := instances[0].Provider.(*fakeProvider).filter; !reflect.DeepEqual(got, filter) {
+		t.Errorf("repo filter = %+v, want %+v", got, filter)
+	}
+}
+
+func TestCheckSelected(t *testing.T) {
+	withFactories(t, fakeFactory("forge"), fakeFactory("single"))
+
+	tests := []struct {
+		names   []string
+		wantErr string
+	}{
+		{names: nil},
+		{names: []string{" Forge ", "single"}},
+		{names: []string{"forge", "githb"}, wantErr: `providers: unknown provider "githb", expected one of forge, single`},
+		{names: []string{"a", "b"}, wantErr: `providers: unknown provider "a", "b", expected one of forge, single`},
+	}
+
+	for _, tt := range tests {
+		err := CheckSelected(tt.names)
+		var got string
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.wantErr {
+			t.Errorf("CheckSelected(%q) = %q, want %q", tt.names, got, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package sourcehut
+
+import (
+	"github.com/vukan322/devmetrics/internal/providers"
+)
+
+func Factory() providers.Factory {
+	return providers.Factory{
+		Name:     "sourcehut",
+		Title:    "SourceHut",
+		Prefix:   "DEV_METRICS_SOURCEHUT",
+		Multiple: true,
+		Keys: []providers.Key{
+			{Name: "USER", Required: true, Usage: "sr.ht username"},
+			{Name: "TOKEN", Required: true, Usage: "personal access token"},
+			{Name: "URL", Default: defaultBaseURL, Usage: "root domain of the instance; git. and todo. are prefixed"},
+		},
+		New: func(cfg providers.Config) (providers.Provider, string, error) {
+			p, err := New(cfg.Get("URL"), cfg.Get("TOKEN"))
+			if err != nil {
+				return nil, "", err
+			}
+			return p, cfg.Get("USER"), nil
+		},
+	}
+}
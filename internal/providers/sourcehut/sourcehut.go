@@ -0,0 +1,456 @@
+package sourcehut
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vukan322/devmetrics/internal/core"
+	"github.com/vukan322/devmetrics/internal/httpclient"
+)
+
+const (
+	defaultBaseURL   = "https://sr.ht"
+	defaultUserAgent = "devmetrics/0.1"
+)
+
+type Provider struct {
+	client  *http.Client
+	gitURL  string
+	todoURL string
+	token   string
+	label   string
+	filter  core.RepoFilter
+}
+
+func New(baseURL, token string) (*Provider, error) {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("sourcehut: parse base url %q: %w", baseURL, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("sourcehut: base url %q has no hostname", baseURL)
+	}
+
+	return &Provider{
+		client:  httpclient.New(),
+		gitURL:  u.Scheme + "://git." + u.Host + "/query",
+		todoURL: u.Scheme + "://todo." + u.Host + "/query",
+		token:   token,
+		label:   httpclient.HostLabel(baseURL, "sourcehut", map[string]string{"sr.ht": "sourcehut"}),
+	}, nil
+}
+
+func (p *Provider) Name() string {
+	return "sourcehut"
+}
+
+func (p *Provider) SetRepoFilter(f core.RepoFilter) {
+	p.filter = f
+}
+
+type srhtUser struct {
+	Username      string    `json:"username"`
+	CanonicalName string    `json:"canonicalName"`
+	Email         string    `json:"email"`
+	Created       time.Time `json:"created"`
+}
+
+type srhtRepo struct {
+	Name       string `json:"name"`
+	Visibility string `json:"visibility"`
+}
+
+type srhtCommit struct {
+	Author struct {
+		Email string    `json:"email"`
+		Time  time.Time `json:"time"`
+	} `json:"author"`
+}
+
+type srhtTicket struct {
+	Status    string `json:"status"`
+	Submitter struct {
+		CanonicalName string `json:"canonicalName"`
+	} `json:"submitter"`
+}
+
+type srhtTracker struct {
+	Name string `json:"name"`
+}
+
+type cursorPage[T any] struct {
+	Results []T     `json:"results"`
+	Cursor  *string `json:"cursor"`
+}
+
+const userReposQuery = `
+query($username: String!, $cursor: Cursor) {
+  user(username: $username) {
+    username
+    canonicalName
+    email
+    created
+    repositories(cursor: $cursor) {
+      results { name visibility }
+      cursor
+    }
+  }
+}`
+
+const repoLogQuery = `
+query($username: String!, $repo: String!, $cursor: Cursor) {
+  user(username: $username) {
+    repository(name: $repo) {
+      log(cursor: $cursor) {
+        results { author { email time } }
+        cursor
+      }
+    }
+  }
+}`
+
+const trackersQuery = `
+query($username: String!, $cursor: Cursor) {
+  user(username: $username) {
+    trackers(cursor: $cursor) {
+      results { name }
+      cursor
+    }
+  }
+}`
+
+const ticketsQuery = `
+query($username: String!, $tracker: String!, $cursor: Cursor) {
+  user(username: $username) {
+    tracker(name: $tracker) {
+      tickets(cursor: $cursor) {
+        results { status submitter { canonicalName } }
+        cursor
+      }
+    }
+  }
+}`
+
+func (p *Provider) Fetch(ctx context.Context, handle string) (core.DevStats, error) {
+	username := strings.TrimPrefix(handle, "~")
+
+	user, repos, err := p.fetchUserRepos(ctx, username)
+	if err != nil {
+		return core.DevStats{}, fmt.Errorf("sourcehut: fetch repos: %w", err)
+	}
+	repos, skipped := p.filterRepos(username, repos)
+
+	publicRepos := 0
+	privateRepos := 0
+	for _, r := range repos {
+		if r.Visibility == "PUBLIC" {
+			publicRepos++
+		} else {
+			privateRepos++
+		}
+	}
+
+	var diag core.Diagnostics
+	diag.Supplied(p.label, core.FieldRepos, core.FieldJoined)
+	diag.Unsupported(p.label, core.FieldStars, core.FieldFollowers, core.FieldContributedRepos, core.FieldLanguages, core.FieldPullRequests, core.FieldReviews, core.FieldPackages)
+
+	contribs := make(map[time.Time]int)
+	totalCommits := 0
+	since := time.Now().UTC().AddDate(-1, 0, 0)
+	commitFailures := 0
+
+	if user.Email == "" {
+		log.Printf("sourcehut: %s hides their email, skipping commits", username)
+		diag.Failed(p.label, core.FieldContributions, fmt.Errorf("profile email of %s is hidden, commits cannot be attributed", username))
+	} else {
+		for _, r := range repos {
+			days, err := p.fetchCommitsPerDay(ctx, username, r.Name, user.Email, since)
+			if err != nil {
+				log.Printf("sourcehut: fetchCommitsPerDay error for %s/%s: %v", username, r.Name, err)
+				commitFailures++
+				continue
+			}
+			for day, count := range days {
+				contribs[day] += count
+				totalCommits += count
+			}
+		}
+		diag.RecordPartial(p.label, core.FieldContributions, commitFailures, len(repos))
+	}
+
+	issueStats, err := p.fetchIssueStats(ctx, username, user.CanonicalName)
+	if err != nil {
+		log.Printf("sourcehut: fetchIssueStats error for %s: %v", username, err)
+		issueStats = core.IssueStats{}
+	}
+	diag.Record(p.label, core.FieldIssues, err)
+
+	currentStreak, longestStreak := core.ComputeStreaks(contribs)
+
+	stats := core.DevStats{
+		Identity: core.Identity{
+			Username: user.Username,
+			Handles:  []string{p.label + ": " + user.CanonicalName},
+		},
+		Totals: core.Totals{
+			PublicRepos:     publicRepos,
+			PrivateRepos:    privateRepos,
+			Joined:          user.Created,
+			Commits:         totalCommits,
+			CurrentStreak:   currentStreak,
+			LongestStreak:   longestStreak,
+			CommitsThisWeek: core.CommitsThisWeek(contribs),
+			FilteredRepos:   len(skipped),
+		},
+		Activity: core.Activity{
+			ContributionsPerDay: contribs,
+			Issues:              issueStats,
+		},
+		Repositories: p.repositories(username, repos),
+		Diagnostics:  diag,
+	}
+
+	return stats, nil
+}
+
+func (p *Provider) filterRepos(username string, repos []srhtRepo) (kept, skipped []srhtRepo) {
+	for _, r := range repos {
+		if p.filter.Keep(core.RepoMeta{Name: r.Name, FullName: "~" + username + "/" + r.Name}) {
+			kept = append(kept, r)
+		} else {
+			skipped = append(skipped, r)
+		}
+	}
+	return kept, skipped
+}
+
+func (p *Provider) repositories(username string, repos []srhtRepo) []core.Repository {
+	base := strings.TrimSuffix(p.gitURL, "/query")
+
+	out := make([]core.Repository, 0, len(repos))
+	for _, r := range repos {
+		out = append(out, core.Repository{
+			Provider: p.label,
+			Name:     "~" + username + "/" + r.Name,
+			URL:      base + "/~" + username + "/" + r.Name,
+			Private:  r.Visibility != "PUBLIC",
+		})
+	}
+	return out
+}
+
+func (p *Provider) fetchUserRepos(ctx context.Context, username string) (*srhtUser, []srhtRepo, error) {
+	var (
+		user   *srhtUser
+		all    []srhtRepo
+		cursor *string
+	)
+
+	for {
+		var data struct {
+			User *struct {
+				srhtUser
+				Repositories cursorPage[srhtRepo] `json:"repositories"`
+			} `json:"user"`
+		}
+
+		vars := map[string]any{"username": username, "cursor": cursor}
+		if err := p.query(ctx, p.gitURL, userReposQuery, vars, &data); err != nil {
+			return nil, nil, err
+		}
+		if data.User == nil {
+			return nil, nil, fmt.Errorf("user %q not found", username)
+		}
+
+		if user == nil {
+			u := data.User.srhtUser
+			user = &u
+		}
+		all = append(all, data.User.Repositories.Results...)
+
+		cursor = data.User.Repositories.Cursor
+		if cursor == nil {
+			break
+		}
+	}
+
+	return user, all, nil
+}
+
+func (p *Provider) fetchCommitsPerDay(ctx context.Context, username, repo, email string, since time.Time) (map[time.Time]int, error) {
+	m := make(map[time.Time]int)
+	var cursor *string
+
+	for {
+		var data struct {
+			User *struct {
+				Repository *struct {
+					Log cursorPage[srhtCommit] `json:"log"`
+				} `json:"repository"`
+			} `json:"user"`
+		}
+
+		vars := map[string]any{"username": username, "repo": repo, "cursor": cursor}
+		if err := p.query(ctx, p.gitURL, repoLogQuery, vars, &data); err != nil {
+			return nil, err
+		}
+		if data.User == nil || data.User.Repository == nil {
+			return m, nil
+		}
+
+		page := data.User.Repository.Log
+		recent := core.CountCommitPage(m, page.Results, since, func(c srhtCommit) (time.Time, bool) {
+			return c.Author.Time, strings.EqualFold(c.Author.Email, email)
+		})
+
+		cursor = page.Cursor
+		if !recent || cursor == nil {
+			break
+		}
+	}
+
+	return m, nil
+}
+
+func (p *Provider) fetchIssueStats(ctx context.Context, username, canonicalName string) (core.IssueStats, error) {
+	var (
+		trackers []srhtTracker
+		cursor   *string
+	)
+
+	for {
+		var data struct {
+			User *struct {
+				Trackers cursorPage[srhtTracker] `json:"trackers"`
+			} `json:"user"`
+		}
+
+		vars := map[string]any{"username": username, "cursor": cursor}
+		if err := p.query(ctx, p.todoURL, trackersQuery, vars, &data); err != nil {
+			return core.IssueStats{}, fmt.Errorf("list trackers: %w", err)
+		}
+		if data.User == nil {
+			return core.IssueStats{}, nil
+		}
+
+		trackers = append(trackers, data.User.Trackers.Results...)
+		cursor = data.User.Trackers.Cursor
+		if cursor == nil {
+			break
+		}
+	}
+
+	var stats core.IssueStats
+	for _, tr := range trackers {
+		cursor = nil
+		for {
+			var data struct {
+				User *struct {
+					Tracker *struct {
+						Tickets cursorPage[srhtTicket] `json:"tickets"`
+					} `json:"tracker"`
+				} `json:"user"`
+			}
+
+			vars := map[string]any{"username": username, "tracker": tr.Name, "cursor": cursor}
+			if err := p.query(ctx, p.todoURL, ticketsQuery, vars, &data); err != nil {
+				return core.IssueStats{}, fmt.Errorf("list tickets for %s: %w", tr.Name, err)
+			}
+			if data.User == nil || data.User.Tracker == nil {
+				break
+			}
+
+			page := data.User.Tracker.Tickets
+			for _, t := range page.Results {
+				if t.Submitter.CanonicalName != canonicalName {
+					continue
+				}
+				if t.Status == "RESOLVED" {
+					stats.Closed++
+				} else {
+					stats.Open++
+				}
+			}
+
+			cursor = page.Cursor
+			if cursor == nil {
+				break
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+func (p *Provider) query(ctx context.Context, endpoint, query string, vars map[string]any, out any) error {
+	buf, err := json.Marshal(map[string]any{
+		"query":     query,
+		"variables": vars,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal graphql body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(buf))
+	if err != nil {
+		return fmt.Errorf("new graphql request: %w", err)
+	}
+	p.applyHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("do graphql request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("unauthorized (401), check DEV_METRICS_SOURCEHUT_TOKEN")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("graphql unexpected status %d from %s body=%s", resp.StatusCode, endpoint, string(body))
+	}
+
+	var r struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return fmt.Errorf("decode graphql response: %w", err)
+	}
+	if len(r.Errors) > 0 {
+		return fmt.Errorf("graphql error: %s", r.Errors[0].Message)
+	}
+
+	if err := json.Unmarshal(r.Data, out); err != nil {
+		return fmt.Errorf("decode graphql data: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Provider) applyHeaders(req *http.Request) {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", defaultUserAgent)
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+}
@@ -0,0 +1,87 @@
+package sourcehut
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func day(n int) time.Time {
+	return time.Date(2026, 3, n, 0, 0, 0, 0, time.UTC)
+}
+
+func TestFetchCommitsPerDayPaging(t *testing.T) {
+	type commit struct {
+		day   int
+		email string
+	}
+
+	// An old merge sits on the first page ahead of newer work, and the last
+	// page must never be requested.
+	pages := [][]commit{
+		{{20, "ada@example.com"}, {2, "ada@example.com"}, {18, "ADA@example.com"}},
+		{{15, "bob@example.com"}, {12, "ada@example.com"}, {1, "ada@example.com"}},
+		{{5, "ada@example.com"}, {4, "ada@example.com"}},
+		{{14, "ada@example.com"}},
+	}
+
+	var requested []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables struct {
+				Cursor *string `json:"cursor"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+
+		index := 0
+		if body.Variables.Cursor != nil {
+			index, _ = strconv.Atoi(*body.Variables.Cursor)
+		}
+		requested = append(requested, index)
+
+		var log cursorPage[srhtCommit]
+		for _, c := range pages[index] {
+			var sc srhtCommit
+			sc.Author.Email = c.email
+			sc.Author.Time = day(c.day).Add(9 * time.Hour)
+			log.Results = append(log.Results, sc)
+		}
+		if index+1 < len(pages) {
+			next := fmt.Sprint(index + 1)
+			log.Cursor = &next
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"user": map[string]any{"repository": map[string]any{"log": log}}},
+		})
+	}))
+	defer srv.Close()
+
+	p, err := New(srv.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.gitURL = srv.URL
+
+	got, err := p.fetchCommitsPerDay(context.Background(), "ada", "engine", "ada@example.com", day(10))
+	if err != nil {
+		t.Fatalf("fetchCommitsPerDay: %v", err)
+	}
+
+	want := map[time.Time]int{day(20): 1, day(18): 1, day(12): 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("commits per day = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(requested, []int{0, 1, 2}) {
+		t.Errorf("requested pages %v, want [0 1 2]", requested)
+	}
+}
@@ -11,8 +11,10 @@ import (
 )
 
 const (
-	svgWidth  = 800
-	svgHeight = 390
+	svgWidth       = 800
+	svgHeight      = 390
+	packagesHeight = 52
+	maxTopPackages = 4
 )
 
 //go:embed templates/devcard.svg.tmpl
@@ -28,6 +30,8 @@ var devcardTmpl = template.Must(
 			"float64": func(i int) float64 { return float64(i) },
 			"divInt":  func(a, b int) int { return a / b },
 			"modInt":  func(a, b int) int { return a % b },
+			"compact": compactNumber,
+			"orDash":  orDash,
 		}).
 		Parse(devcardTemplate),
 )
@@ -55,11 +59,19 @@ type devcardViewModel struct {
 	PROpen   int
 	PRMerged int
 	PRClosed int
+	Reviews  int
 
 	Commits         int
 	CurrentStreak   int
 	LongestStreak   int
 	CommitsThisWeek int
+
+	PackageCount   int
+	TotalDownloads int64
+	HasDownloads   bool
+	TopPackages    []core.PackageStat
+
+	Known map[string]bool
 }
 
 func RenderSVG(stats core.DevStats) ([]byte, error) {
@@ -71,9 +83,27 @@ func RenderSVG(stats core.DevStats) ([]byte, error) {
 
 	langs := stats.Activity.TopLanguages
 
+	height := svgHeight
+	if stats.Packages.Count > 0 {
+		height += packagesHeight
+	}
+
+	hasDownloads := false
+	for _, pkg := range stats.Packages.Top {
+		if !pkg.DownloadsUnknown {
+			hasDownloads = true
+			break
+		}
+	}
+
+	topPackages := stats.Packages.Top
+	if len(topPackages) > maxTopPackages {
+		topPackages = topPackages[:maxTopPackages]
+	}
+
 	vm := devcardViewModel{
 		Width:            svgWidth,
-		Height:           svgHeight,
+		Height:           height,
 		Title:            title,
 		Subtitle:         subtitle,
 		AvatarURL:        stats.Identity.Avatar,
@@ -82,7 +112,7 @@ func RenderSVG(stats core.DevStats) ([]byte, error) {
 		Stars:            stats.Totals.Stars,
 		Followers:        stats.Totals.Followers,
 		ContributedRepos: stats.Totals.ContributedRepos,
-		JoinedAgo:        stats.Totals.JoinedAgo,
+		JoinedAgo:        core.FormatJoinedAgo(stats.Totals.Joined),
 		TotalLanguages:   stats.Totals.TotalLanguages,
 		Languages:        langs,
 		IssuesOpen:       stats.Activity.Issues.Open,
@@ -90,10 +120,16 @@ func RenderSVG(stats core.DevStats) ([]byte, error) {
 		PROpen:           stats.Activity.PullRequests.Open,
 		PRMerged:         stats.Activity.PullRequests.Merged,
 		PRClosed:         stats.Activity.PullRequests.Closed,
+		Reviews:          stats.Totals.Reviews,
 		Commits:          stats.Totals.Commits,
 		CurrentStreak:    stats.Totals.CurrentStreak,
 		LongestStreak:    stats.Totals.LongestStreak,
 		CommitsThisWeek:  stats.Totals.CommitsThisWeek,
+		PackageCount:     stats.Packages.Count,
+		TotalDownloads:   stats.Packages.TotalDownloads,
+		HasDownloads:     hasDownloads,
+		TopPackages:      topPackages,
+		Known:            knownFields(stats.Diagnostics),
 	}
 
 	var buf bytes.Buffer
@@ -102,3 +138,48 @@ func RenderSVG(stats core.DevStats) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+func knownFields(d core.Diagnostics) map[string]bool {
+	fields := []core.Field{
+		core.FieldRepos,
+		core.FieldStars,
+		core.FieldFollowers,
+		core.FieldContributedRepos,
+		core.FieldJoined,
+		core.FieldLanguages,
+		core.FieldContributions,
+		core.FieldIssues,
+		core.FieldPullRequests,
+		core.FieldReviews,
+		core.FieldPackages,
+	}
+
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[string(f)] = d.Known(f)
+	}
+	return known
+}
+
+func orDash(known bool, v any) string {
+	if !known {
+		return "—"
+	}
+	if s, ok := v.(string); ok && s == "" {
+		return "—"
+	}
+	return fmt.Sprint(v)
+}
+
+func compactNumber(n int64) string {
+	switch {
+	case n >= 1_000_000_000:
+		return fmt.Sprintf("%.1fB", float64(n)/1_000_000_000)
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}